@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/admein-advertising/admein-vast-generator/validator"
+	"github.com/admein-advertising/admein-vast-generator/vast"
+)
+
+// versionRule mirrors the generated vast.versionRule entries.
+type versionRule struct {
+	since vast.Version
+	node  string
+}
+
+func main() {
+	// Run from repo root: go run ./cmd/vastversiongen -out vast/versions_gen.go
+	outPath := flag.String("out", "vast/versions_gen.go", "output file for generated version rules")
+	flag.Parse()
+
+	data, err := generate(validator.DefaultVASTCatalog())
+	if err != nil {
+		log.Fatalf("version rule generation failed: %v", err)
+	}
+	if err := os.WriteFile(*outPath, data, 0o644); err != nil {
+		log.Fatalf("writing output failed: %v", err)
+	}
+}
+
+// generate flattens the catalog into the minimum version of every child
+// element ("Parent>Child") and attribute ("Parent@attr").
+func generate(catalog *validator.Catalog) ([]byte, error) {
+	rules := map[string]versionRule{}
+	for key, spec := range catalog.Nodes {
+		for name, attr := range spec.Attributes {
+			if attr.Namespace != "" {
+				continue
+			}
+			rules[key+"@"+name] = versionRule{since: minimumVersion(attr.Versions)}
+		}
+		for name, child := range spec.Children {
+			nodeKey := name
+			if child.NodeOverride != "" {
+				nodeKey = child.NodeOverride
+			}
+			since := minimumVersion(child.Versions)
+			if node, ok := catalog.Node(nodeKey); ok {
+				if nodeSince := minimumVersion(node.Versions); versionLess(since, nodeSince) {
+					since = nodeSince
+				}
+			} else {
+				nodeKey = ""
+			}
+			rules[key+">"+name] = versionRule{since: since, node: nodeKey}
+		}
+	}
+
+	keys := make([]string, 0, len(rules))
+	for key := range rules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by cmd/vastversiongen. DO NOT EDIT.\n")
+	buf.WriteString("package vast\n\n")
+	buf.WriteString("var catalogVersionRules = map[string]versionRule{\n")
+	for _, key := range keys {
+		rule := rules[key]
+		if rule.node == "" {
+			fmt.Fprintf(&buf, "\t%s: {Since: %q},\n", strconv.Quote(key), rule.since)
+			continue
+		}
+		fmt.Fprintf(&buf, "\t%s: {Since: %q, Node: %q},\n", strconv.Quote(key), rule.since, rule.node)
+	}
+	buf.WriteString("}\n")
+	return format.Source(buf.Bytes())
+}
+
+func minimumVersion(versions []vast.Version) vast.Version {
+	var minimum vast.Version
+	for _, version := range versions {
+		if minimum == "" || versionLess(version, minimum) {
+			minimum = version
+		}
+	}
+	return minimum
+}
+
+func versionLess(a, b vast.Version) bool {
+	x, _ := strconv.ParseFloat(string(a), 64)
+	y, _ := strconv.ParseFloat(string(b), 64)
+	return x < y
+}
@@ -15,28 +15,28 @@ var (
 	patternCache    sync.Map
 )
 
-func validateAttributeValue(attrName, value string, spec *AttributeSpec) []string {
+func validateAttributeValue(attrName, value string, spec *AttributeSpec) []Reason {
 	if spec == nil || spec.Value == nil {
 		return nil
 	}
 	valSpec := spec.Value
-	var errs []string
+	var errs []Reason
 	if valSpec.Type != "" {
 		if err := validateAttributeType(value, valSpec.Type); err != "" {
-			errs = append(errs, fmt.Sprintf("attribute %s expects %s: %s", attrName, valSpec.Type, err))
+			errs = append(errs, newReason(ReasonInvalidAttributeValue, "attribute {attribute} expects {type}: {error}", "attribute", attrName, "value", value, "type", valSpec.Type, "error", err))
 		}
 	}
 	if len(valSpec.AllowedValues) > 0 {
 		if !containsString(valSpec.AllowedValues, value) {
-			errs = append(errs, fmt.Sprintf("attribute %s must be one of %v", attrName, valSpec.AllowedValues))
+			errs = append(errs, newReason(ReasonInvalidAttributeValue, "attribute {attribute} must be one of {expected}", "attribute", attrName, "value", value, "expected", valSpec.AllowedValues))
 		}
 	}
 	if valSpec.Pattern != "" {
 		re, err := getCachedPattern(valSpec.Pattern)
 		if err != nil {
-			errs = append(errs, fmt.Sprintf("attribute %s misconfigured pattern: %v", attrName, err))
+			errs = append(errs, newReason(ReasonInvalidAttributeValue, "attribute {attribute} misconfigured pattern: {error}", "attribute", attrName, "value", value, "error", err))
 		} else if !re.MatchString(value) {
-			errs = append(errs, fmt.Sprintf("attribute %s must match pattern %s", attrName, valSpec.Pattern))
+			errs = append(errs, newReason(ReasonInvalidAttributeValue, "attribute {attribute} must match pattern {expected}", "attribute", attrName, "value", value, "expected", valSpec.Pattern))
 		}
 	}
 	return errs
@@ -107,9 +107,8 @@ func containsString(values []string, needle string) bool {
 }
 
 func isKeyword(value string, accepted []string) bool {
-	lower := strings.ToLower(value)
 	for _, candidate := range accepted {
-		if lower == strings.ToLower(candidate) {
+		if strings.EqualFold(value, candidate) {
 			return true
 		}
 	}
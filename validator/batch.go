@@ -0,0 +1,53 @@
+package validator
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// ValidateBatch validates docs concurrently using at most GOMAXPROCS workers.
+// Results and errors are returned in input order; a document that could not be
+// validated has a nil result and a non-nil error. Options are applied once, so
+// every document shares the same HTTP client. Documents not yet started when ctx
+// is cancelled report ctx.Err(); documents already running stop their HTTP
+// validators, which report ReasonCanceled.
+func ValidateBatch(ctx context.Context, docs [][]byte, opts ...Option) ([]*ValidationResult, []error) {
+	results := make([]*ValidationResult, len(docs))
+	errs := make([]error, len(docs))
+	if len(docs) == 0 {
+		return results, errs
+	}
+
+	base := newConfig(opts...)
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(docs) {
+		workers = len(docs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					errs[i] = err
+					continue
+				}
+				// Each document gets its own copy so per-run state such as the
+				// fail-fast flag and the selected catalog does not leak between them.
+				cfg := *base
+				cfg.httpContext = ctx
+				results[i], errs[i] = validateWithConfig(docs[i], &cfg)
+			}
+		}()
+	}
+	for i := range docs {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, errs
+}
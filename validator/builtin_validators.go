@@ -0,0 +1,949 @@
+package validator
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/admein-advertising/admein-vast-generator/vast"
+)
+
+// builtInValidatorFunc runs a rule shipped with the validator. Unlike custom
+// validators it receives the active configuration so opt-in rules can consult
+// their options.
+type builtInValidatorFunc func(ctx NodeContext, cfg *config) *NodeAnalysisResult
+
+// defaultStaticResourceTypes lists the creativeType values players commonly
+// support for StaticResource, including script types used by older specs.
+var defaultStaticResourceTypes = []string{
+	"image/gif",
+	"image/jpeg",
+	"image/png",
+	"image/webp",
+	"image/svg+xml",
+	"image/bmp",
+	"application/x-javascript",
+	"application/javascript",
+	"text/javascript",
+	"application/x-shockwave-flash",
+}
+
+// builtInValidators holds rules that complement the catalog checks, keyed by lower-cased node name.
+var builtInValidators = map[string][]builtInValidatorFunc{}
+
+func init() {
+	registerBuiltInValidators()
+}
+
+func registerBuiltInValidators() {
+	registerBuiltInValidator("Companion", companionAltTextValidator)
+	registerBuiltInValidator("Companion", companionResourceValidator)
+	registerBuiltInValidator("Icon", iconLayoutValidator)
+	for name := range resourceSlotParents {
+		registerBuiltInValidator(name, mixedResourceValidator)
+	}
+	registerBuiltInValidator("StaticResource", staticResourceCreativeTypeValidator)
+	registerBuiltInValidator("Ad", adTrackingIDUniquenessValidator)
+	registerBuiltInValidator("Ad", adTypeValidator)
+	registerBuiltInValidator("Category", categoryValidator)
+	registerBuiltInValidator("BlockedAdCategories", blockedAdCategoriesValidator)
+	registerBuiltInValidator("JavaScriptResource", verificationJavaScriptResourceValidator)
+	registerBuiltInValidator("InteractiveCreativeFile", interactiveCreativeFileValidator)
+	registerBuiltInValidator("ViewableImpression", viewableImpressionValidator)
+	registerBuiltInValidator("IconClicks", iconClicksValidator)
+	registerBuiltInValidator("Mezzanine", mezzanineTypeValidator)
+	registerBuiltInValidator("ClosedCaptionFile", closedCaptionFileValidator)
+	registerBuiltInValidator("Survey", surveyValidator)
+	registerBuiltInValidator("Error", errorCodeMacroValidator)
+	registerBuiltInValidator("AdSystem", adSystemAuditValidator)
+	registerBuiltInValidator("InLine", adServingIDValidator)
+	registerBuiltInValidator("Linear", wrapperLinearValidator)
+	registerBuiltInValidator("MediaFiles", wrapperMediaValidator)
+	registerBuiltInValidator("Mezzanine", wrapperMediaValidator)
+	registerBuiltInValidator("Creative", inLineCreativeTypeValidator)
+	registerBuiltInValidator("CompanionAds", companionAdsRequiredValidator)
+	registerBuiltInValidator("Tracking", trackingOffsetValidator)
+	registerBuiltInValidator("MediaFiles", inLineMediaFilesValidator)
+	registerBuiltInValidator("VAST", rootNamespaceValidator)
+	registerBuiltInValidator("VAST", adIDUniquenessValidator)
+	registerBuiltInValidator("VAST", maxAdsValidator)
+	registerBuiltInValidator("Duration", inLineDurationValidator)
+	registerBuiltInValidator("NonLinear", nonLinearMinSuggestedDurationValidator)
+	for _, name := range vpaidNodes {
+		registerBuiltInValidator(name, vpaidValidator)
+	}
+	for _, name := range macroURLNodes {
+		registerBuiltInValidator(name, unreplacedMacroValidator)
+	}
+	for _, name := range secureURLNodes {
+		registerBuiltInValidator(name, insecureURLValidator)
+	}
+}
+
+func registerBuiltInValidator(nodeName string, validator builtInValidatorFunc) {
+	key := strings.ToLower(nodeName)
+	builtInValidators[key] = append(builtInValidators[key], validator)
+}
+
+// versionAtLeast reports whether version is numerically at or above minimum.
+func versionAtLeast(version, minimum vast.Version) bool {
+	current, ok := vastVersionToFloat(version)
+	if !ok {
+		return false
+	}
+	floor, ok := vastVersionToFloat(minimum)
+	return ok && current >= floor
+}
+
+func applyBuiltInValidators(nodeResult *NodeResult, node *genericNode, version vast.Version, cfg *config) {
+	validators := builtInValidators[strings.ToLower(nodeResult.Node)]
+	if len(validators) == 0 {
+		return
+	}
+	ctx := NodeContext{Node: node, Version: version}
+	for _, validator := range validators {
+		analysis := validator(ctx, cfg)
+		if analysis == nil {
+			continue
+		}
+		if analysis.Category == "" {
+			analysis.Category = IABAnalysisCategory
+		}
+		mergeAnalysis(nodeResult, analysis)
+	}
+}
+
+// trackingOffsetValidator fails a progress Tracking without an offset, which
+// players need to know when to fire it, and warns when another event carries an
+// offset that players ignore.
+func trackingOffsetValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	event, ok := ctx.Attribute("event")
+	if !ok {
+		return nil
+	}
+	event = strings.TrimSpace(event)
+	offset, hasOffset := ctx.Attribute("offset")
+	hasOffset = hasOffset && strings.TrimSpace(offset) != ""
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	switch {
+	case strings.EqualFold(event, string(vast.ProgressEvent)):
+		if !hasOffset {
+			markFailure(analysis, ReasonInvalidTrackingOffset, "Tracking event {event} requires an offset", "event", vast.ProgressEvent)
+		}
+	case hasOffset:
+		markWarning(analysis, ReasonInvalidTrackingOffset, "Tracking offset is only used by progress events and is ignored on {event}", "event", event)
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// vpaidNodes lists the elements whose apiFramework attribute can select VPAID.
+var vpaidNodes = []string{"Creative", "MediaFile", "InteractiveCreativeFile", "NonLinear", "Companion", "Icon"}
+
+// vpaidValidator warns where VPAID is declared, since many publishers reject
+// VPAID creatives, noting its deprecation from 4.1. InteractiveCreativeFile
+// already flags VPAID from 4.1.
+func vpaidValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil {
+		return nil
+	}
+	name := ctx.Node.localName()
+	if name == "InteractiveCreativeFile" && versionAtLeast(ctx.Version, vast.Version41) {
+		return nil
+	}
+	apiFramework, _ := ctx.Attribute("apiFramework")
+	if !strings.EqualFold(strings.TrimSpace(apiFramework), vast.VPAIDAPIFramework) {
+		return nil
+	}
+	message, args := "{node} uses VPAID, which many publishers reject", []any{"node", name}
+	if url := strings.TrimSpace(ctx.Node.Content); url != "" {
+		message = "{node} {url} uses VPAID, which many publishers reject"
+		args = append(args, "url", url)
+	}
+	if versionAtLeast(ctx.Version, vast.Version41) {
+		message += "; VPAID is deprecated in VAST {version}"
+		args = append(args, "version", ctx.Version)
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonVPAID, message, args...)
+	return analysis
+}
+
+// companionAdsRequiredValidator warns when CompanionAds asks the player to show
+// all or any of its companions but contains none. The required value itself is
+// checked against its allowed values by the catalog.
+func companionAdsRequiredValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	required, ok := ctx.Attribute("required")
+	required = strings.ToLower(strings.TrimSpace(required))
+	if !ok || (required != "all" && required != "any") || ctx.HasChildNamed("Companion") {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonMissingCompanion, `CompanionAds required="{value}" has no Companion elements`, "value", required)
+	return analysis
+}
+
+// companionAltTextValidator warns when an image companion omits AltText, which
+// screen readers rely on to describe the creative.
+func companionAltTextValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	hasImage := false
+	for _, resource := range ctx.ChildrenNamed("StaticResource") {
+		creativeType, _ := resource.attrValue("creativeType")
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(creativeType)), "image/") {
+			hasImage = true
+			break
+		}
+	}
+	if !hasImage {
+		return nil
+	}
+	for _, altText := range ctx.ChildrenNamed("AltText") {
+		if strings.TrimSpace(altText.Content) != "" {
+			return nil
+		}
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonMissingAltText, "Companion with an image StaticResource should include AltText for accessibility")
+	return analysis
+}
+
+// resourceNodes lists the resource types a Companion, NonLinear or Icon chooses from.
+var resourceNodes = []string{"StaticResource", "IFrameResource", "HTMLResource"}
+
+// resourceSlotParents maps each node that carries a single creative resource to
+// the container it appears in.
+var resourceSlotParents = map[string]string{
+	"Companion": "CompanionAds",
+	"NonLinear": "NonLinearAds",
+	"Icon":      "Icons",
+}
+
+// presentResources returns the resource types among the node's children.
+func presentResources(ctx NodeContext) []string {
+	var present []string
+	for _, name := range resourceNodes {
+		if ctx.HasChildNamed(name) {
+			present = append(present, name)
+		}
+	}
+	return present
+}
+
+// companionResourceValidator fails a Companion without a resource. It applies to
+// InLine and Wrapper companions alike.
+func companionResourceValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil || ctx.ParentName() != "CompanionAds" || len(presentResources(ctx)) > 0 {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markFailure(analysis, ReasonMissingResource, "{node} must contain one of {expected}", "node", "Companion", "expected", strings.Join(resourceNodes, ", "))
+	return analysis
+}
+
+// mixedResourceValidator warns when a Companion, NonLinear or Icon mixes
+// resource types, since its slot renders exactly one resource kind.
+func mixedResourceValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil {
+		return nil
+	}
+	name := ctx.Node.localName()
+	if ctx.ParentName() != resourceSlotParents[name] {
+		return nil
+	}
+	present := presentResources(ctx)
+	if len(present) < 2 {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonMultipleResources, "{node} should contain only one of {expected}; found {found}", "node", name, "expected", strings.Join(resourceNodes, ", "), "found", strings.Join(present, ", "))
+	return analysis
+}
+
+// iconLayoutAttributes lists the Icon attributes a player needs to size and
+// place the icon.
+var iconLayoutAttributes = []string{"width", "height", "xPosition", "yPosition"}
+
+// iconLayoutValidator warns when an Icon omits any of its dimensions or
+// position, which the catalog treats as optional but players need to render it.
+func iconLayoutValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	var missing []string
+	for _, name := range iconLayoutAttributes {
+		if _, ok := ctx.Attribute(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonIncompleteIconLayout, "Icon should declare {expected} to render; missing {missing}", "expected", strings.Join(iconLayoutAttributes, ", "), "missing", strings.Join(missing, ", "))
+	return analysis
+}
+
+// staticResourceCreativeTypeValidator warns when StaticResource declares a
+// creativeType players are unlikely to render.
+func staticResourceCreativeTypeValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	creativeType, ok := ctx.Attribute("creativeType")
+	creativeType = strings.TrimSpace(creativeType)
+	if ok && creativeType == "" {
+		// Empty attribute values are already reported by the catalog checks.
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	if !ok {
+		markWarning(analysis, ReasonInvalidCreativeType, "StaticResource should declare a creativeType")
+		return analysis
+	}
+	allowed := defaultStaticResourceTypes
+	if cfg != nil && len(cfg.staticResourceTypes) > 0 {
+		allowed = cfg.staticResourceTypes
+	}
+	if !isKeyword(creativeType, allowed) {
+		markWarning(analysis, ReasonInvalidCreativeType, "StaticResource creativeType {value} is not a supported image or script type", "value", creativeType)
+		return analysis
+	}
+	return nil
+}
+
+// adTypeValidator fails an Ad that does not contain exactly one of InLine or Wrapper.
+func adTypeValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil {
+		return nil
+	}
+	inlines := len(ctx.ChildrenNamed("InLine"))
+	wrappers := len(ctx.ChildrenNamed("Wrapper"))
+	if inlines+wrappers == 1 {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	switch {
+	case inlines > 0 && wrappers > 0:
+		markFailure(analysis, ReasonInvalidAdType, "Ad must contain either InLine or Wrapper, not both")
+	case inlines+wrappers == 0:
+		markFailure(analysis, ReasonInvalidAdType, "Ad must contain an InLine or Wrapper element")
+	default:
+		markFailure(analysis, ReasonInvalidAdType, "Ad must contain exactly one InLine or Wrapper element")
+	}
+	return analysis
+}
+
+var domainPattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]*[a-z0-9])?(\.[a-z0-9]([a-z0-9-]*[a-z0-9])?)*\.[a-z]{2,}$`)
+
+// categoryValidator warns when a Category has no code or its authority does not
+// look like a taxonomy domain or URI such as https://iabtechlab.com.
+func categoryValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	if ctx.Text() == "" {
+		markWarning(analysis, ReasonInvalidCategory, "Category should contain a category code")
+	}
+	if authority, ok := ctx.Attribute("authority"); ok && strings.TrimSpace(authority) != "" {
+		if !isPlausibleAuthority(strings.TrimSpace(authority)) {
+			markWarning(analysis, ReasonInvalidCategory, "Category authority {value} is not a plausible domain or URI", "value", authority)
+		}
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// isPlausibleAuthority reports whether value is a domain name or an absolute
+// URI whose host is a domain name.
+func isPlausibleAuthority(value string) bool {
+	if domainPattern.MatchString(value) {
+		return true
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Scheme == "" {
+		return false
+	}
+	return domainPattern.MatchString(parsed.Hostname())
+}
+
+// blockedAdCategoriesValidator fails a Wrapper BlockedAdCategories without a
+// category code and warns when the code has no authority to qualify it.
+func blockedAdCategoriesValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if !strings.EqualFold(ctx.ParentName(), "Wrapper") {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	if ctx.Text() == "" {
+		markFailure(analysis, ReasonInvalidCategory, "BlockedAdCategories must contain a category code")
+	}
+	if authority, ok := ctx.Attribute("authority"); !ok || strings.TrimSpace(authority) == "" {
+		markWarning(analysis, ReasonInvalidCategory, "BlockedAdCategories should declare an authority; the category code is ambiguous without one")
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// verificationJavaScriptResourceValidator warns when a Verification script does
+// not declare the OMID framework, which 4.1+ players require to load it.
+func verificationJavaScriptResourceValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if !strings.EqualFold(ctx.ParentName(), "Verification") || !versionAtLeast(ctx.Version, vast.Version41) {
+		return nil
+	}
+	apiFramework, ok := ctx.Attribute("apiFramework")
+	apiFramework = strings.TrimSpace(apiFramework)
+	if ok && strings.EqualFold(apiFramework, "omid") {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	if apiFramework == "" {
+		markWarning(analysis, ReasonInvalidAPIFramework, `Verification JavaScriptResource should declare apiFramework="omid"`)
+	} else {
+		markWarning(analysis, ReasonInvalidAPIFramework, `Verification JavaScriptResource apiFramework {value} should be "omid"`, "value", apiFramework)
+	}
+	return analysis
+}
+
+// interactiveAPIFrameworks lists the frameworks players use to run an InteractiveCreativeFile.
+var interactiveAPIFrameworks = []string{"SIMID", "VPAID"}
+
+// interactiveCreativeFileValidator warns when an InteractiveCreativeFile has an
+// unknown apiFramework or no type, and flags VPAID as deprecated from 4.1.
+func interactiveCreativeFileValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	apiFramework, _ := ctx.Attribute("apiFramework")
+	apiFramework = strings.TrimSpace(apiFramework)
+	switch {
+	case apiFramework == "":
+		markWarning(analysis, ReasonInvalidAPIFramework, "InteractiveCreativeFile should declare an apiFramework (one of {expected})", "expected", strings.Join(interactiveAPIFrameworks, ", "))
+	case !isKeyword(apiFramework, interactiveAPIFrameworks):
+		markWarning(analysis, ReasonInvalidAPIFramework, "InteractiveCreativeFile apiFramework {value} is not recognized; expected one of {expected}", "value", apiFramework, "expected", strings.Join(interactiveAPIFrameworks, ", "))
+	case strings.EqualFold(apiFramework, "VPAID") && versionAtLeast(ctx.Version, vast.Version41):
+		markWarning(analysis, ReasonInvalidAPIFramework, "InteractiveCreativeFile apiFramework {value} is deprecated in VAST {version}; use SIMID", "value", "VPAID", "version", ctx.Version)
+	}
+	if mimeType, _ := ctx.Attribute("type"); strings.TrimSpace(mimeType) == "" {
+		markWarning(analysis, ReasonInvalidCreativeType, "InteractiveCreativeFile should declare a type")
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// wrapperLinearInLineOnlyNodes lists Linear children that only an InLine ad may
+// carry; a Wrapper's Linear holds tracking and clicks only.
+var wrapperLinearInLineOnlyNodes = []string{"MediaFiles", "Duration"}
+
+// wrapperLinearValidator fails a Linear under Wrapper that carries MediaFiles or
+// Duration, which belong to the InLine ad the wrapper resolves to.
+func wrapperLinearValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if !ctx.HasAncestorNamed("Wrapper") {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	for _, name := range wrapperLinearInLineOnlyNodes {
+		if ctx.HasChildNamed(name) {
+			markFailure(analysis, ReasonInlineOnlyNode, "Linear under Wrapper must not contain {child}", "child", name)
+		}
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// wrapperMediaValidator fails MediaFiles and Mezzanine anywhere in a Wrapper
+// subtree: a wrapper must not serve media itself. MediaFiles directly under a
+// Linear are already failed by wrapperLinearValidator.
+func wrapperMediaValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if !ctx.HasAncestorNamed("Wrapper") {
+		return nil
+	}
+	name := ctx.Node.localName()
+	if name == "MediaFiles" && ctx.ParentName() == "Linear" {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markFailure(analysis, ReasonInlineOnlyNode, "{child} must not appear under Wrapper", "child", name)
+	return analysis
+}
+
+// adIDUniquenessValidator fails the root when two of its Ad children share a
+// non-empty id, which breaks per-ad reporting.
+func adIDUniquenessValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	seen := map[string]int{}
+	for _, ad := range ctx.ChildrenNamed("Ad") {
+		id, _ := ad.attrValue("id")
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		seen[id]++
+		if seen[id] == 2 {
+			markFailure(analysis, ReasonDuplicateAdID, `Ad id "{id}" is used by more than one Ad`, "id", id)
+		}
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// maxAdsValidator fails a VAST document carrying more Ad elements than allowed
+// by WithMaxAds.
+func maxAdsValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	if cfg == nil || cfg.maxAds <= 0 {
+		return nil
+	}
+	count := len(ctx.ChildrenNamed("Ad"))
+	if count <= cfg.maxAds {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markFailure(analysis, ReasonTooManyAds, "VAST contains {count} ads; at most {limit} are allowed", "count", count, "limit", cfg.maxAds)
+	return analysis
+}
+
+// rootNamespaceValidator warns when a 4.x root omits the IAB VAST namespace or
+// declares another one, and when xsi attributes are used without binding xsi to
+// the XML Schema instance namespace.
+func rootNamespaceValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil || ctx.ParentName() != "" {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	if versionAtLeast(ctx.Version, vast.Version40) {
+		switch namespace, ok := ctx.Attribute("xmlns"); {
+		case !ok:
+			markWarning(analysis, ReasonInvalidNamespace, `VAST {version} root should declare xmlns="{expected}"`, "version", ctx.Version, "expected", vastNamespaceURL)
+		case strings.TrimSpace(namespace) != vastNamespaceURL:
+			markWarning(analysis, ReasonInvalidNamespace, "VAST root namespace {value} does not match {expected}", "value", namespace, "expected", vastNamespaceURL)
+		}
+	}
+	usesXSI := false
+	xsiBinding, xsiDeclared := "", false
+	for _, attr := range ctx.Node.Attrs {
+		switch {
+		case attr.Name.Space == "xmlns" && attr.Name.Local == "xsi":
+			xsiBinding, xsiDeclared = strings.TrimSpace(attr.Value), true
+		case attr.Name.Space == "xsi" || attr.Name.Space == xsiNamespaceURL:
+			usesXSI = true
+		}
+	}
+	switch {
+	case xsiDeclared && xsiBinding != xsiNamespaceURL:
+		markWarning(analysis, ReasonInvalidNamespace, "VAST root binds xmlns:xsi to {value}; expected {expected}", "value", xsiBinding, "expected", xsiNamespaceURL)
+	case usesXSI && !xsiDeclared:
+		markWarning(analysis, ReasonInvalidNamespace, `VAST root uses xsi attributes without declaring xmlns:xsi="{expected}"`, "expected", xsiNamespaceURL)
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// inLineDurationValidator fails an InLine Linear Duration that is not a valid
+// hh:mm:ss value. An empty Duration is already failed by the catalog.
+func inLineDurationValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.ParentName() != "Linear" || !ctx.HasAncestorNamed("InLine") {
+		return nil
+	}
+	value := ctx.Text()
+	if value == "" {
+		return nil
+	}
+	err := vast.Duration(value).ValidateDuration()
+	if err == nil {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markFailure(analysis, ReasonInvalidDuration, "Duration {value} is invalid: {error}", "value", value, "error", err)
+	return analysis
+}
+
+// nonLinearMinSuggestedDurationValidator fails a NonLinear whose
+// minSuggestedDuration has hours, minutes or seconds out of range, e.g. 00:75:00.
+// Unlike a Linear Duration, short and zero values are allowed. Values that do
+// not match the duration format are already failed by the catalog checks.
+func nonLinearMinSuggestedDurationValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	value, ok := ctx.Attribute("minSuggestedDuration")
+	value = strings.TrimSpace(value)
+	if !ok || !durationPattern.MatchString(value) {
+		return nil
+	}
+	var problem string
+	switch {
+	case value[0:2] > "23":
+		problem = "hours must be between 00 and 23"
+	case value[3:5] > "59":
+		problem = "minutes must be between 00 and 59"
+	case value[6:8] > "59":
+		problem = "seconds must be between 00 and 59"
+	default:
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markFailure(analysis, ReasonInvalidDuration, "NonLinear minSuggestedDuration {value} is invalid: {error}", "value", value, "error", problem)
+	return analysis
+}
+
+// inLineMediaFilesValidator fails an InLine Linear's MediaFiles without any
+// MediaFile, which leaves the player nothing to play. MediaFiles under Wrapper
+// are already failed by wrapperLinearValidator.
+func inLineMediaFilesValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.ParentName() != "Linear" || !ctx.HasAncestorNamed("InLine") || ctx.HasChildNamed("MediaFile") {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markFailure(analysis, ReasonMissingMediaFile, "MediaFiles under InLine Linear must contain at least one MediaFile")
+	return analysis
+}
+
+// creativeTypeNodes lists the children that give an InLine Creative its type.
+var creativeTypeNodes = []string{"Linear", "NonLinearAds", "CompanionAds"}
+
+// inLineCreativeTypeValidator fails an InLine Creative without a Linear,
+// NonLinearAds or CompanionAds child and warns when it has more than one.
+// Wrapper creatives may be tracking-only and are not checked.
+func inLineCreativeTypeValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil || ctx.ParentName() != "Creatives" || !ctx.HasAncestorNamed("InLine") {
+		return nil
+	}
+	var present []string
+	for _, name := range creativeTypeNodes {
+		if ctx.HasChildNamed(name) {
+			present = append(present, name)
+		}
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	switch {
+	case len(present) == 0:
+		markFailure(analysis, ReasonInvalidCreative, "InLine Creative must contain one of {expected}", "expected", strings.Join(creativeTypeNodes, ", "))
+	case len(present) > 1:
+		markWarning(analysis, ReasonInvalidCreative, "InLine Creative should contain only one of {expected}; found {found}", "expected", strings.Join(creativeTypeNodes, ", "), "found", strings.Join(present, ", "))
+	default:
+		return nil
+	}
+	return analysis
+}
+
+// adSystemAuditValidator notes the ad server version carried by AdSystem and
+// warns when AdSystem does not name the ad server. It only runs when
+// WithAdSystemAudit is set.
+func adSystemAuditValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	if cfg == nil || !cfg.auditAdSystem || ctx.Node == nil {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	name := strings.TrimSpace(ctx.Node.Content)
+	if name == "" {
+		markWarning(analysis, ReasonMissingAdSystem, "AdSystem should name the ad server")
+		return analysis
+	}
+	version, _ := ctx.Attribute("version")
+	version = strings.TrimSpace(version)
+	switch {
+	case version == "":
+		markInformational(analysis, ReasonAdSystemAudit, "AdSystem {name} does not report a version", "name", name)
+	case version == string(ctx.Version):
+		markInformational(analysis, ReasonAdSystemAudit, "AdSystem {name} version {version} matches the VAST version; it should identify the ad server version", "name", name, "version", version)
+	default:
+		markInformational(analysis, ReasonAdSystemAudit, "AdSystem {name} version {version}", "name", name, "version", version)
+	}
+	return analysis
+}
+
+// adServingIDValidator warns when a 4.1+ InLine has no AdServingId, or only an
+// empty one. It only runs when WithAdServingIDCheck is set.
+func adServingIDValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	if cfg == nil || !cfg.requireAdServingID || ctx.Node == nil || !versionAtLeast(ctx.Version, vast.Version41) {
+		return nil
+	}
+	for _, id := range ctx.ChildrenNamed("AdServingId") {
+		if strings.TrimSpace(id.Content) != "" {
+			return nil
+		}
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonMissingAdServingID, "InLine should include a non-empty AdServingId in VAST {version}", "version", ctx.Version)
+	return analysis
+}
+
+// mezzanineMIMETypes lists the raw, high-quality container formats accepted for
+// Mezzanine files, which ad-stitching servers transcode themselves.
+var mezzanineMIMETypes = []string{
+	"video/mp4",
+	"video/quicktime",
+	"video/mxf",
+	"application/mxf",
+}
+
+// mezzanineTypeValidator warns when a 4.1+ Mezzanine declares a type outside the
+// raw mezzanine formats. Missing required attributes are failed by the catalog.
+func mezzanineTypeValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if !versionAtLeast(ctx.Version, vast.Version41) {
+		return nil
+	}
+	mimeType, ok := ctx.Attribute("type")
+	mimeType = strings.TrimSpace(mimeType)
+	if !ok || mimeType == "" || isKeyword(mimeType, mezzanineMIMETypes) {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonInvalidMezzanineType, "Mezzanine type {value} is not a raw mezzanine format; expected one of {expected}", "value", mimeType, "expected", strings.Join(mezzanineMIMETypes, ", "))
+	return analysis
+}
+
+// captionMIMETypes lists the caption formats players commonly render.
+var captionMIMETypes = []string{
+	"text/vtt",
+	"application/ttml+xml",
+	"text/srt",
+	"application/x-subrip",
+}
+
+// languageTagPattern loosely matches a BCP-47 language tag such as en, en-US or
+// zh-Hant-TW.
+var languageTagPattern = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// closedCaptionFileValidator warns when a ClosedCaptionFile declares a type
+// that is not a caption format or a language that is not a BCP-47 tag. Empty
+// attribute values are already reported by the catalog checks.
+func closedCaptionFileValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	mimeType, _ := ctx.Attribute("type")
+	mimeType = strings.TrimSpace(mimeType)
+	if mimeType != "" && !isKeyword(mimeType, captionMIMETypes) {
+		markWarning(analysis, ReasonInvalidCaptionType, "ClosedCaptionFile type {value} is not a caption format; expected one of {expected}", "value", mimeType, "expected", strings.Join(captionMIMETypes, ", "))
+	}
+	language, _ := ctx.Attribute("language")
+	language = strings.TrimSpace(language)
+	if language != "" && !languageTagPattern.MatchString(language) {
+		markWarning(analysis, ReasonInvalidLanguageTag, "ClosedCaptionFile language {value} is not a BCP-47 language tag", "value", language)
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// mimeTypePattern matches a type/subtype MIME value, optionally followed by
+// parameters such as "; charset=utf-8".
+var mimeTypePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]*/[A-Za-z0-9][A-Za-z0-9!#$&^_.+-]*(\s*;.*)?$`)
+
+// surveyValidator warns that Survey is deprecated from 4.1 and that its type,
+// when present, is not a MIME type.
+func surveyValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	if versionAtLeast(ctx.Version, vast.Version41) {
+		markWarning(analysis, ReasonDeprecatedNode, "Survey is deprecated in VAST {version}", "version", ctx.Version)
+	}
+	mimeType, _ := ctx.Attribute("type")
+	mimeType = strings.TrimSpace(mimeType)
+	if mimeType != "" && !mimeTypePattern.MatchString(mimeType) {
+		markWarning(analysis, ReasonInvalidMIMEType, "Survey type {value} is not a MIME type", "value", mimeType)
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// viewableImpressionNodes lists the URL children of ViewableImpression.
+var viewableImpressionNodes = []string{"Viewable", "NotViewable", "ViewUndetermined"}
+
+// viewableImpressionValidator warns when ViewableImpression carries no URLs and
+// fails when one of its URL elements is empty.
+func viewableImpressionValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	urls := 0
+	for _, child := range ctx.Node.Children {
+		if !isKeyword(child.localName(), viewableImpressionNodes) {
+			continue
+		}
+		urls++
+		if strings.TrimSpace(child.Content) == "" {
+			markFailure(analysis, ReasonEmptyURL, "ViewableImpression {child} URL is empty", "child", child.localName())
+		}
+	}
+	if urls == 0 {
+		markWarning(analysis, ReasonMissingViewableURL, "ViewableImpression should contain at least one Viewable, NotViewable or ViewUndetermined URL")
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// iconClicksValidator fails an empty IconClickTracking URL and warns when click
+// tracking is present without a non-empty IconClickThrough to send the viewer to.
+func iconClicksValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	tracking := ctx.ChildrenNamed("IconClickTracking")
+	for _, child := range tracking {
+		if strings.TrimSpace(child.Content) == "" {
+			markFailure(analysis, ReasonEmptyURL, "IconClicks {child} URL is empty", "child", "IconClickTracking")
+		}
+	}
+	if len(tracking) > 0 {
+		clickThrough := ""
+		for _, child := range ctx.ChildrenNamed("IconClickThrough") {
+			clickThrough += strings.TrimSpace(child.Content)
+		}
+		if clickThrough == "" {
+			markWarning(analysis, ReasonMissingClickThrough, "IconClicks has IconClickTracking but no IconClickThrough URL")
+		}
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// trackingIDNodes lists the beacon nodes whose id attributes must be unique within an Ad.
+var trackingIDNodes = []string{"Impression", "Tracking", "ClickTracking"}
+
+// adTrackingIDUniquenessValidator warns when beacons share an id within a
+// single Ad, which breaks downstream de-duplication. Ids are compared across
+// beacon kinds, so an Impression and a ClickTracking may not share one.
+func adTrackingIDUniquenessValidator(ctx NodeContext, _ *config) *NodeAnalysisResult {
+	if ctx.Node == nil {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	seen := map[string]int{}
+	ctx.Node.walk(func(node *genericNode) bool {
+		if !isKeyword(node.localName(), trackingIDNodes) {
+			return true
+		}
+		id, ok := node.attrValue("id")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return true
+		}
+		seen[id]++
+		if seen[id] == 2 {
+			markWarning(analysis, ReasonDuplicateTrackingID, `{child} id "{id}" is used more than once in this Ad`, "child", node.localName(), "id", id)
+		}
+		return true
+	})
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// macroURLNodes lists the nodes whose text is a URL that may carry macros.
+var macroURLNodes = []string{
+	"Impression", "Error", "Tracking", "VASTAdTagURI",
+	"ClickThrough", "ClickTracking", "CustomClick",
+	"Viewable", "NotViewable", "ViewUndetermined",
+	"NonLinearClickThrough", "NonLinearClickTracking",
+	"CompanionClickThrough", "CompanionClickTracking",
+	"IconClickThrough", "IconClickTracking", "IconViewTracking",
+}
+
+// secureURLNodes lists the nodes whose text is a URL checked by
+// WithRequireHTTPS: the macro URL nodes plus media files and resources.
+var secureURLNodes = append([]string{
+	"MediaFile", "Mezzanine", "InteractiveCreativeFile", "ClosedCaptionFile",
+	"StaticResource", "IFrameResource", "JavaScriptResource", "ExecutableResource",
+}, macroURLNodes...)
+
+// insecureURLValidator warns when WithRequireHTTPS is set and a URL node uses
+// http:// or a protocol-relative URL, which inherits http on insecure pages.
+func insecureURLValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	if cfg == nil || !cfg.requireHTTPS {
+		return nil
+	}
+	value := ctx.Text()
+	var message string
+	switch {
+	case strings.HasPrefix(value, "//"):
+		message = "{node} URL is protocol-relative; use https"
+	case len(value) >= len("http://") && strings.EqualFold(value[:len("http://")], "http://"):
+		message = "{node} URL uses http; use https"
+	default:
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonInsecureURL, message, "node", ctx.Node.localName(), "url", value)
+	return analysis
+}
+
+// defaultPlayerMacros lists the IAB macros a player is expected to expand at
+// request or beacon time.
+var defaultPlayerMacros = []string{
+	"TIMESTAMP", "CACHEBUSTING", "ERRORCODE", "REASON", "CONTENTPLAYHEAD",
+	"MEDIAPLAYHEAD", "ADPLAYHEAD", "BREAKPOSITION", "BREAKMAXDURATION",
+	"BREAKMINDURATION", "BREAKMAXADS", "BREAKMAXADLENGTH", "BREAKMINADLENGTH",
+	"ADCOUNT", "ADSERVINGID", "ADTYPE", "TRANSACTIONID", "PLACEMENTTYPE",
+	"ASSETURI", "PODSEQUENCE", "UNIVERSALADID", "PLAYERSTATE", "PLAYERSIZE",
+	"CLICKPOS", "CLICKTYPE", "INVENTORYSTATE", "APIFRAMEWORKS", "EXTENSIONS",
+	"VERIFICATIONVENDORS", "OMIDPARTNER", "MEDIAMIME", "PLAYBACKMETHODS",
+	"LIMITADTRACKING", "DEVICEUA", "DEVICEIP", "SERVERUA", "SERVERSIDE",
+	"APPBUNDLE", "DOMAIN", "PAGEURL", "CONTENTID", "CONTENTURI", "IFA", "IFATYPE",
+	"LATLONG", "GDPRCONSENT", "REGULATIONS", "BLOCKEDADCATEGORIES",
+}
+
+var macroPattern = regexp.MustCompile(`\[([A-Z_]+)\]`)
+
+// unreplacedMacroValidator warns about bracket macros in URLs that the ad server
+// should have expanded before serving. It only runs when WithMacroPolicy is set.
+func unreplacedMacroValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	if cfg == nil || !cfg.checkMacros || ctx.Node == nil {
+		return nil
+	}
+	allowed := defaultPlayerMacros
+	if len(cfg.allowedMacros) > 0 {
+		allowed = cfg.allowedMacros
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	seen := map[string]bool{}
+	for _, match := range macroPattern.FindAllStringSubmatch(ctx.Node.Content, -1) {
+		name := match[1]
+		if seen[name] || isMacroAllowed(name, allowed) {
+			continue
+		}
+		seen[name] = true
+		markWarning(analysis, ReasonUnreplacedMacro, "{node} URL contains unreplaced macro [{macro}]", "node", ctx.Node.localName(), "macro", name)
+	}
+	if analysis.Status == StatusPass {
+		return nil
+	}
+	return analysis
+}
+
+// errorCodeParents lists the elements whose Error children are error beacons.
+var errorCodeParents = []string{"VAST", "InLine", "Wrapper"}
+
+// errorCodeMacroValidator warns when an Error beacon URL lacks [ERRORCODE]. It
+// only runs when WithErrorCodeMacroCheck is set.
+func errorCodeMacroValidator(ctx NodeContext, cfg *config) *NodeAnalysisResult {
+	if cfg == nil || !cfg.checkErrorCodeMacro || ctx.Node == nil {
+		return nil
+	}
+	if !isKeyword(ctx.ParentName(), errorCodeParents) {
+		return nil
+	}
+	content := strings.TrimSpace(ctx.Node.Content)
+	if content == "" || strings.Contains(content, "[ERRORCODE]") {
+		return nil
+	}
+	analysis := &NodeAnalysisResult{Category: IABAnalysisCategory, Status: StatusPass}
+	markWarning(analysis, ReasonMissingErrorCodeMacro, "Error URL under {parent} should include the [ERRORCODE] macro", "parent", ctx.ParentName())
+	return analysis
+}
+
+func isMacroAllowed(name string, allowed []string) bool {
+	for _, candidate := range allowed {
+		if strings.Trim(strings.TrimSpace(candidate), "[]") == name {
+			return true
+		}
+	}
+	return false
+}
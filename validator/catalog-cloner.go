@@ -51,6 +51,7 @@ func cloneAttributeSpec(src *AttributeSpec) *AttributeSpec {
 		Versions:      cloneVersions(src.Versions),
 		Required:      src.Required,
 		AllowEmpty:    src.AllowEmpty,
+		Namespace:     src.Namespace,
 		Value:         cloneAttributeValueSpec(src.Value),
 		Documentation: cloneDocumentation(src.Documentation),
 	}
@@ -65,6 +66,9 @@ func cloneAttributeValueSpec(src *AttributeValueSpec) *AttributeValueSpec {
 		cloned.AllowedValues = make([]string, len(src.AllowedValues))
 		copy(cloned.AllowedValues, src.AllowedValues)
 	}
+	if len(src.KnownValues) > 0 {
+		cloned.KnownValues = append([]string(nil), src.KnownValues...)
+	}
 	return cloned
 }
 
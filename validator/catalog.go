@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/admein-advertising/admein-vast-generator/vast"
@@ -33,16 +34,22 @@ type Documentation struct {
 type AttributeValueSpec struct {
 	Type          AttributeType
 	AllowedValues []string
+	// KnownValues lists the conventional values of an open attribute; other
+	// values are reported as warnings rather than failures.
+	KnownValues   []string
 	Pattern       string
 	Documentation *Documentation
 }
 
 // AttributeSpec describes a valid attribute for a node.
 type AttributeSpec struct {
-	Name          string
-	Versions      []vast.Version
-	Required      bool
-	AllowEmpty    bool
+	Name       string
+	Versions   []vast.Version
+	Required   bool
+	AllowEmpty bool
+	// Namespace optionally restricts the attribute to an XML namespace URI. Such
+	// specs are keyed and named by their prefixed form, e.g. "xml:lang".
+	Namespace     string `json:",omitempty"`
 	Value         *AttributeValueSpec
 	Documentation *Documentation
 }
@@ -84,6 +91,12 @@ func DefaultVASTCatalog() *Catalog {
 	return cloned
 }
 
+// Node returns the spec registered under name, matching case-sensitively, and
+// whether the catalog defines it.
+func (c *Catalog) Node(name string) (*NodeSpec, bool) {
+	return c.node(name)
+}
+
 func (c *Catalog) node(name string) (*NodeSpec, bool) {
 	if c == nil {
 		return nil, false
@@ -104,6 +117,31 @@ func (c *Catalog) nodeCaseInsensitive(name string) (*NodeSpec, string, bool) {
 	return nil, "", false
 }
 
+// parentsOf returns the sorted names of the nodes that list name as a child.
+func (c *Catalog) parentsOf(name string) []string {
+	if c == nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var parents []string
+	for _, spec := range c.Nodes {
+		if _, ok := spec.Children[name]; ok && !seen[spec.Name] {
+			seen[spec.Name] = true
+			parents = append(parents, spec.Name)
+		}
+	}
+	sort.Strings(parents)
+	return parents
+}
+
+// SupportedVersions returns a copy of the versions in which the node is valid.
+func (spec *NodeSpec) SupportedVersions() []vast.Version {
+	if spec == nil {
+		return nil
+	}
+	return append([]vast.Version(nil), spec.Versions...)
+}
+
 func (spec *NodeSpec) supports(version vast.Version) bool {
 	for _, v := range spec.Versions {
 		if v == version {
@@ -171,6 +209,11 @@ func (spec *AttributeSpec) supports(version vast.Version) bool {
 	return false
 }
 
+// knownAPIFrameworks lists the IAB API frameworks apiFramework usually names.
+// The attribute is open, so other values warn rather than fail. VPAID is
+// reported separately by vpaidValidator.
+var knownAPIFrameworks = []string{"VPAID", "SIMID", "MRAID", "OMID"}
+
 var (
 	supported20Plus = []vast.Version{
 		vast.Version20,
@@ -206,6 +249,20 @@ var (
 	vast42SchemaURL = "https://raw.githubusercontent.com/InteractiveAdvertisingBureau/vast/refs/heads/master/vast_4.2.xsd"
 )
 
+// xmlLangAttribute is the qualified catalog name of the xml:lang attribute.
+const xmlLangAttribute = "xml:lang"
+
+// xmlLangAttributeSpec describes xml:lang, which descriptive text nodes may carry
+// to declare the language of their content.
+func xmlLangAttributeSpec() *AttributeSpec {
+	return &AttributeSpec{
+		Name:      xmlLangAttribute,
+		Versions:  supported20Plus,
+		Namespace: xmlNamespaceURL,
+		Value:     &AttributeValueSpec{Type: AttributeTypeToken, Pattern: `^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`},
+	}
+}
+
 // defaultCatalog contains a subset of the IAB VAST specification, focused on the
 // most common nodes used by this project. Additional nodes can be appended over
 // time without changing the validator API.
@@ -293,9 +350,10 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		NeedsCDATA: true,
 	},
 	"Impression": {
-		Name:       "Impression",
-		Versions:   supported20Plus,
-		NeedsCDATA: true,
+		Name:          "Impression",
+		Versions:      supported20Plus,
+		NeedsCDATA:    true,
+		RequiresValue: true,
 		Attributes: map[string]*AttributeSpec{
 			"id": {Name: "id", Versions: supported30Plus},
 		},
@@ -304,6 +362,9 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		Name:          "AdTitle",
 		Versions:      supported20Plus,
 		RequiresValue: true,
+		Attributes: map[string]*AttributeSpec{
+			xmlLangAttribute: xmlLangAttributeSpec(),
+		},
 	},
 	"AdServingId": {
 		Name:          "AdServingId",
@@ -321,7 +382,9 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		Name:     "Category",
 		Versions: supported30Plus,
 		Attributes: map[string]*AttributeSpec{
-			"authority": {Name: "authority", Versions: supported30Plus, Required: true, Value: &AttributeValueSpec{Type: AttributeTypeURI}},
+			// authority is often a bare domain such as iabtechlab.com; categoryValidator
+			// warns when it is neither a domain nor a URI.
+			"authority": {Name: "authority", Versions: supported30Plus, Required: true},
 		},
 	},
 	"BlockedAdCategories": {
@@ -335,6 +398,9 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		Name:       "Description",
 		Versions:   supported20Plus,
 		NeedsCDATA: true,
+		Attributes: map[string]*AttributeSpec{
+			xmlLangAttribute: xmlLangAttributeSpec(),
+		},
 	},
 	"Survey": {
 		Name:     "Survey",
@@ -444,7 +510,7 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		Versions:   supported40Plus,
 		NeedsCDATA: true,
 		Attributes: map[string]*AttributeSpec{
-			"apiFramework": {Name: "apiFramework", Versions: supported30Plus},
+			"apiFramework": {Name: "apiFramework", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 			"type":         {Name: "type", Versions: supported30Plus},
 			"language":     {Name: "language", Versions: supported41Plus},
 		},
@@ -455,9 +521,10 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		NeedsCDATA: true,
 	},
 	"VASTAdTagURI": {
-		Name:       "VASTAdTagURI",
-		Versions:   supported20Plus,
-		NeedsCDATA: true,
+		Name:          "VASTAdTagURI",
+		Versions:      supported20Plus,
+		NeedsCDATA:    true,
+		RequiresValue: true,
 	},
 	"Creatives": {
 		Name:     "Creatives",
@@ -479,7 +546,7 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		Attributes: map[string]*AttributeSpec{
 			"id":           {Name: "id", Versions: supported20Plus},
 			"sequence":     {Name: "sequence", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
-			"apiFramework": {Name: "apiFramework", Versions: supported20Plus},
+			"apiFramework": {Name: "apiFramework", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 			"AdID":         {Name: "AdID", Versions: supported20Plus},
 		},
 		Children: map[string]*ChildSpec{
@@ -496,7 +563,7 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		Attributes: map[string]*AttributeSpec{
 			"id":           {Name: "id", Versions: supported20Plus},
 			"sequence":     {Name: "sequence", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
-			"apiFramework": {Name: "apiFramework", Versions: supported20Plus},
+			"apiFramework": {Name: "apiFramework", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 			"AdID":         {Name: "AdID", Versions: supported20Plus},
 		},
 		Children: map[string]*ChildSpec{
@@ -527,12 +594,12 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 			"scalable":             {Name: "scalable", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeBoolean}},
 			"maintainAspectRatio":  {Name: "maintainAspectRatio", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeBoolean}},
 			"minSuggestedDuration": {Name: "minSuggestedDuration", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeDuration}},
-			"apiFramework":         {Name: "apiFramework", Versions: supported20Plus},
+			"apiFramework":         {Name: "apiFramework", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 		},
 		Children: map[string]*ChildSpec{
-			"StaticResource":         {Name: "StaticResource", Versions: supported20Plus, Optional: true},
-			"IFrameResource":         {Name: "IFrameResource", Versions: supported20Plus, Optional: true},
-			"HTMLResource":           {Name: "HTMLResource", Versions: supported20Plus, Optional: true},
+			"StaticResource":         {Name: "StaticResource", Versions: supported20Plus, Optional: true, Multiple: true},
+			"IFrameResource":         {Name: "IFrameResource", Versions: supported20Plus, Optional: true, Multiple: true},
+			"HTMLResource":           {Name: "HTMLResource", Versions: supported20Plus, Optional: true, Multiple: true},
 			"AdParameters":           {Name: "AdParameters", Versions: supported20Plus, Optional: true},
 			"NonLinearClickTracking": {Name: "NonLinearClickTracking", Versions: supported30Plus, Optional: true, Multiple: true},
 			"NonLinearClickThrough":  {Name: "NonLinearClickThrough", Versions: supported20Plus, Optional: true},
@@ -558,7 +625,7 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 			"required": {Name: "required", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, AllowedValues: []string{"all", "any", "none"}}},
 		},
 		Children: map[string]*ChildSpec{
-			"Companion": {Name: "Companion", Versions: supported20Plus, Multiple: true},
+			"Companion": {Name: "Companion", Versions: supported20Plus, Optional: true, Multiple: true},
 		},
 	},
 	"Companion": {
@@ -568,7 +635,7 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 			"id":             {Name: "id", Versions: supported20Plus},
 			"width":          {Name: "width", Versions: supported20Plus, Required: true, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
 			"height":         {Name: "height", Versions: supported20Plus, Required: true, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
-			"apiFramework":   {Name: "apiFramework", Versions: supported20Plus},
+			"apiFramework":   {Name: "apiFramework", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 			"assetWidth":     {Name: "assetWidth", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
 			"assetHeight":    {Name: "assetHeight", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
 			"expandedWidth":  {Name: "expandedWidth", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypePositiveInteger}},
@@ -578,9 +645,9 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 			"renderingMode":  {Name: "renderingMode", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, AllowedValues: []string{"default", "end-card", "concurrent"}}},
 		},
 		Children: map[string]*ChildSpec{
-			"StaticResource":         {Name: "StaticResource", Versions: supported20Plus, Optional: true},
-			"IFrameResource":         {Name: "IFrameResource", Versions: supported20Plus, Optional: true},
-			"HTMLResource":           {Name: "HTMLResource", Versions: supported20Plus, Optional: true},
+			"StaticResource":         {Name: "StaticResource", Versions: supported20Plus, Optional: true, Multiple: true},
+			"IFrameResource":         {Name: "IFrameResource", Versions: supported20Plus, Optional: true, Multiple: true},
+			"HTMLResource":           {Name: "HTMLResource", Versions: supported20Plus, Optional: true, Multiple: true},
 			"AdParameters":           {Name: "AdParameters", Versions: supported20Plus, Optional: true},
 			"AltText":                {Name: "AltText", Versions: supported20Plus, Optional: true},
 			"CompanionClickThrough":  {Name: "CompanionClickThrough", Versions: supported20Plus, Optional: true},
@@ -604,6 +671,9 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 	"AltText": {
 		Name:     "AltText",
 		Versions: supported20Plus,
+		Attributes: map[string]*AttributeSpec{
+			xmlLangAttribute: xmlLangAttributeSpec(),
+		},
 	},
 	"Icons": {
 		Name:     "Icons",
@@ -623,13 +693,13 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 			"yPosition":    {Name: "yPosition", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, Pattern: "([0-9]*|top|bottom)"}},
 			"duration":     {Name: "duration", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeDuration}},
 			"offset":       {Name: "offset", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeDuration}},
-			"apiFramework": {Name: "apiFramework", Versions: supported30Plus},
+			"apiFramework": {Name: "apiFramework", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 			"pxratio":      {Name: "pxratio", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeFloat}},
 		},
 		Children: map[string]*ChildSpec{
-			"StaticResource":   {Name: "StaticResource", Versions: supported30Plus, Optional: true},
-			"IFrameResource":   {Name: "IFrameResource", Versions: supported30Plus, Optional: true},
-			"HTMLResource":     {Name: "HTMLResource", Versions: supported30Plus, Optional: true},
+			"StaticResource":   {Name: "StaticResource", Versions: supported30Plus, Optional: true, Multiple: true},
+			"IFrameResource":   {Name: "IFrameResource", Versions: supported30Plus, Optional: true, Multiple: true},
+			"HTMLResource":     {Name: "HTMLResource", Versions: supported30Plus, Optional: true, Multiple: true},
 			"IconClicks":       {Name: "IconClicks", Versions: supported30Plus, Optional: true},
 			"IconViewTracking": {Name: "IconViewTracking", Versions: supported30Plus, Optional: true, Multiple: true},
 		},
@@ -751,23 +821,25 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 		NeedsCDATA: true,
 	},
 	"Duration": {
-		Name:     "Duration",
-		Versions: supported20Plus,
+		Name:          "Duration",
+		Versions:      supported20Plus,
+		RequiresValue: true,
 	},
 	"MediaFiles": {
 		Name:     "MediaFiles",
 		Versions: supported20Plus,
 		Children: map[string]*ChildSpec{
-			"MediaFile":               {Name: "MediaFile", Versions: supported20Plus, Multiple: true},
+			"MediaFile":               {Name: "MediaFile", Versions: supported20Plus, Optional: true, Multiple: true},
 			"ClosedCaptionFiles":      {Name: "ClosedCaptionFiles", Versions: supported30Plus, Optional: true},
 			"Mezzanine":               {Name: "Mezzanine", Versions: supported40Plus, Optional: true, Multiple: true},
 			"InteractiveCreativeFile": {Name: "InteractiveCreativeFile", Versions: supported30Plus, Optional: true, Multiple: true},
 		},
 	},
 	"MediaFile": {
-		Name:       "MediaFile",
-		Versions:   supported20Plus,
-		NeedsCDATA: true,
+		Name:          "MediaFile",
+		Versions:      supported20Plus,
+		NeedsCDATA:    true,
+		RequiresValue: true,
 		Attributes: map[string]*AttributeSpec{
 			"id":                  {Name: "id", Versions: supported20Plus},
 			"delivery":            {Name: "delivery", Versions: supported20Plus, Required: true, Value: &AttributeValueSpec{Type: AttributeTypeToken, AllowedValues: []string{"progressive", "streaming"}}},
@@ -782,7 +854,7 @@ var defaultCatalog = &Catalog{Nodes: map[string]*NodeSpec{
 			"maintainAspectRatio": {Name: "maintainAspectRatio", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeBoolean}},
 			"fileSize":            {Name: "fileSize", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeNonNegativeInteger}},
 			"mediaType":           {Name: "mediaType", Versions: supported30Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, AllowedValues: []string{"2D", "3D"}}},
-			"apiFramework":        {Name: "apiFramework", Versions: supported20Plus},
+			"apiFramework":        {Name: "apiFramework", Versions: supported20Plus, Value: &AttributeValueSpec{Type: AttributeTypeToken, KnownValues: knownAPIFrameworks}},
 		},
 	},
 	"ClosedCaptionFiles": {
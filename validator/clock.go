@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"context"
+	"time"
+)
+
+// Clock supplies the time used by HTTP validator timeouts, retry backoff and
+// timings. WithClock replaces the real clock, e.g. with a fake one in tests.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clock returns the configured clock, defaulting to the real one.
+func (cfg *config) clock() Clock {
+	if cfg == nil || cfg.clockOverride == nil {
+		return realClock{}
+	}
+	return cfg.clockOverride
+}
+
+// withClockTimeout cancels ctx with cause once d elapses on clock. The real
+// clock uses context.WithTimeoutCause so probes also carry a deadline for the
+// transport.
+func withClockTimeout(ctx context.Context, clock Clock, d time.Duration, cause error) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeoutCause(ctx, d, cause)
+	}
+	ctx, cancel := context.WithCancelCause(ctx)
+	expired := clock.After(d)
+	go func() {
+		select {
+		case <-expired:
+			cancel(cause)
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+type clockContextKey struct{}
+
+// contextWithClock exposes the active clock to the probe retry loop.
+func contextWithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockContextKey{}, clock)
+}
+
+func clockFromContext(ctx context.Context) Clock {
+	if clock, ok := ctx.Value(clockContextKey{}).(Clock); ok {
+		return clock
+	}
+	return realClock{}
+}
@@ -2,7 +2,9 @@ package validator
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
@@ -15,6 +17,8 @@ const (
 	IABAnalysisCategory = "iab.analysis"
 	// CustomAnalysisCategory is the default bucket for caller-supplied validators.
 	CustomAnalysisCategory = "custom.analysis"
+	// VersionAnalysisCategory holds the root summary added by WithVersionMismatchSummary.
+	VersionAnalysisCategory = "version.analysis"
 )
 
 // NodeContext provides context to custom validators.
@@ -39,15 +43,60 @@ func (ctx NodeContext) Attribute(name string) (string, bool) {
 	return ctx.Node.attrValue(name)
 }
 
+// ChildrenNamed returns the direct children matching name (case-insensitive).
+func (ctx NodeContext) ChildrenNamed(name string) []*genericNode {
+	if ctx.Node == nil {
+		return nil
+	}
+	var children []*genericNode
+	for _, child := range ctx.Node.Children {
+		if strings.EqualFold(child.localName(), name) {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// ParentName returns the local name of the enclosing element, or an empty
+// string for the document root.
+func (ctx NodeContext) ParentName() string {
+	if ctx.Node == nil || ctx.Node.Parent == nil {
+		return ""
+	}
+	return ctx.Node.Parent.localName()
+}
+
+// HasAncestorNamed reports whether any enclosing element is named name.
+func (ctx NodeContext) HasAncestorNamed(name string) bool {
+	if ctx.Node == nil {
+		return false
+	}
+	for parent := ctx.Node.Parent; parent != nil; parent = parent.Parent {
+		if strings.EqualFold(parent.localName(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasChildNamed reports whether the node has at least one direct child named name.
+func (ctx NodeContext) HasChildNamed(name string) bool {
+	return len(ctx.ChildrenNamed(name)) > 0
+}
+
 // NodeValidatorFunc runs custom validation logic on a node.
 type NodeValidatorFunc func(ctx NodeContext) *NodeAnalysisResult
 
 // HTTPValidatorFunc represents a validator that performs HTTP requests (e.g., HEAD checks).
 type HTTPValidatorFunc func(ctx context.Context, nodeCtx NodeContext, client *http.Client) (*NodeAnalysisResult, error)
 
+// AttributeValidatorFunc runs custom validation logic on a single attribute value.
+type AttributeValidatorFunc func(value string, ctx NodeContext) *NodeAnalysisResult
+
 var (
-	customMu         sync.RWMutex
-	customValidators = map[string][]NodeValidatorFunc{}
+	customMu            sync.RWMutex
+	customValidators    = map[string][]NodeValidatorFunc{}
+	attributeValidators = map[string][]AttributeValidatorFunc{}
 )
 
 // RegisterCustomValidator registers a custom validator for the given node name.
@@ -68,6 +117,29 @@ func getCustomValidators(nodeName string) []NodeValidatorFunc {
 	return append([]NodeValidatorFunc(nil), customValidators[strings.ToLower(nodeName)]...)
 }
 
+// RegisterAttributeValidator registers a custom validator for the named attribute
+// of the given node. It only runs when the attribute is present and, like node
+// validators, reports into CustomAnalysisCategory unless it sets a category.
+func RegisterAttributeValidator(nodeName, attrName string, validator func(value string, ctx NodeContext) *NodeAnalysisResult) {
+	if validator == nil {
+		return
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	key := attributeValidatorKey(nodeName, attrName)
+	attributeValidators[key] = append(attributeValidators[key], validator)
+}
+
+func getAttributeValidators(nodeName, attrName string) []AttributeValidatorFunc {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	return append([]AttributeValidatorFunc(nil), attributeValidators[attributeValidatorKey(nodeName, attrName)]...)
+}
+
+func attributeValidatorKey(nodeName, attrName string) string {
+	return strings.ToLower(nodeName) + "/" + strings.ToLower(attrName)
+}
+
 // HTTPValidatorRegistry stores HTTP-based validators keyed by node name.
 var HTTPValidatorRegistry = struct {
 	mu    sync.RWMutex
@@ -95,11 +167,120 @@ func getHTTPValidators(nodeName string) []HTTPValidatorFunc {
 type HTTPValidationOptions struct {
 	Client  *http.Client
 	Timeout time.Duration
+	// RecordTimings captures the wall-clock duration of each HTTP validator on
+	// its analysis and aggregates the total on the ValidationResult.
+	RecordTimings bool
+	// MaxRetries is the number of additional attempts made when a probe fails
+	// with a network error or 5xx response. 4xx responses are never retried.
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles on each attempt.
+	RetryBackoff time.Duration
+	// PerNodeTimeout overrides Timeout for HTTP validators of the named nodes,
+	// e.g. a longer deadline for Mezzanine. Node names match case-insensitively.
+	PerNodeTimeout map[string]time.Duration
+	// OverallTimeout caps the total time spent in HTTP validators for a single
+	// document. Validators that would run after it elapses record StatusInfo.
+	OverallTimeout time.Duration
+	// Proxy routes probes through the given proxy URL. Ignored when Client is set.
+	Proxy *url.URL
+	// TLSConfig configures TLS for probes, e.g. to present a client certificate.
+	// Ignored when Client is set.
+	TLSConfig *tls.Config
+	// InsecureSkipVerify disables TLS certificate verification for probes, e.g.
+	// against staging CDNs with self-signed certificates. It makes probes
+	// vulnerable to interception and must not be used against production hosts.
+	// Ignored when Client is set.
+	InsecureSkipVerify bool
+	// SkipHosts lists hosts whose URLs are not probed; the node records an info
+	// note instead. Entries match case-insensitively; an entry with a leading dot
+	// (".internal") matches every subdomain.
+	SkipHosts []string
+	// SkipSchemes lists URL schemes, such as "rtmp", that are not probed.
+	SkipSchemes []string
+	// MaxProbeBodyBytes caps how much of a response body is read when a probe
+	// falls back to a ranged GET, guarding against servers that ignore the
+	// Range header and stream the whole file. Zero uses a 4 KiB default.
+	MaxProbeBodyBytes int64
+}
+
+// skipsProbe reports whether rawURL matches SkipHosts or SkipSchemes.
+func (opts *HTTPValidationOptions) skipsProbe(rawURL string) bool {
+	if opts == nil || (len(opts.SkipHosts) == 0 && len(opts.SkipSchemes) == 0) {
+		return false
+	}
+	trimmed := strings.TrimSpace(rawURL)
+	if strings.HasPrefix(trimmed, "//") {
+		trimmed = "https:" + trimmed
+	}
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return false
+	}
+	for _, scheme := range opts.SkipSchemes {
+		if strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(scheme), ":"), parsed.Scheme) {
+			return true
+		}
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return false
+	}
+	for _, skip := range opts.SkipHosts {
+		skip = strings.ToLower(strings.TrimSpace(skip))
+		if skip == "" {
+			continue
+		}
+		if host == skip || (strings.HasPrefix(skip, ".") && strings.HasSuffix(host, skip)) {
+			return true
+		}
+	}
+	return false
 }
 
 func (opts *HTTPValidationOptions) client() *http.Client {
-	if opts == nil || opts.Client == nil {
+	if opts == nil {
 		return http.DefaultClient
 	}
-	return opts.Client
+	if opts.Client != nil {
+		return opts.Client
+	}
+	if !opts.customTransport() {
+		return http.DefaultClient
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.Proxy != nil {
+		transport.Proxy = http.ProxyURL(opts.Proxy)
+	}
+	if opts.TLSConfig != nil {
+		transport.TLSClientConfig = opts.TLSConfig.Clone()
+	}
+	if opts.InsecureSkipVerify {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	return &http.Client{Transport: transport}
+}
+
+// customTransport reports whether the options require a client other than
+// http.DefaultClient.
+func (opts *HTTPValidationOptions) customTransport() bool {
+	return opts.Proxy != nil || opts.TLSConfig != nil || opts.InsecureSkipVerify
+}
+
+// timeoutFor returns the timeout applied to HTTP validators of the named node.
+func (opts *HTTPValidationOptions) timeoutFor(nodeName string) time.Duration {
+	if opts == nil {
+		return 0
+	}
+	if timeout, ok := opts.PerNodeTimeout[nodeName]; ok {
+		return timeout
+	}
+	for name, timeout := range opts.PerNodeTimeout {
+		if strings.EqualFold(name, nodeName) {
+			return timeout
+		}
+	}
+	return opts.Timeout
 }
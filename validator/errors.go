@@ -0,0 +1,40 @@
+package validator
+
+// ValidateErrorKind classifies the errors returned by Validate.
+type ValidateErrorKind string
+
+const (
+	// ParseError indicates the document is empty or is not well-formed XML.
+	ParseError ValidateErrorKind = "parse"
+	// RootError indicates the root element is not one the validator accepts.
+	RootError ValidateErrorKind = "root"
+	// VersionError indicates the root element is missing a usable version attribute.
+	VersionError ValidateErrorKind = "version"
+	// InternalError indicates validation panicked; the document could not be
+	// validated.
+	InternalError ValidateErrorKind = "internal"
+)
+
+// ValidateError wraps an error returned by Validate with its Kind so callers can
+// branch on the error class with errors.As. The wrapped error remains reachable
+// through errors.Is, e.g. errors.Is(err, ErrInvalidRoot).
+type ValidateError struct {
+	Kind ValidateErrorKind
+	Err  error
+}
+
+func (e *ValidateError) Error() string {
+	if e.Err == nil {
+		return string(e.Kind) + " error"
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ValidateError) Unwrap() error {
+	return e.Err
+}
+
+func newValidateError(kind ValidateErrorKind, err error) *ValidateError {
+	return &ValidateError{Kind: kind, Err: err}
+}
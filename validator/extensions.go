@@ -115,23 +115,6 @@ func (ctx ExtensionValidationContext) Type() string {
 	return strings.TrimSpace(value)
 }
 
-func (ctx ExtensionValidationContext) ChildrenNamed(name string) []*genericNode {
-	if ctx.Node == nil {
-		return nil
-	}
-	var children []*genericNode
-	for _, child := range ctx.Node.Children {
-		if strings.EqualFold(child.localName(), name) {
-			children = append(children, child)
-		}
-	}
-	return children
-}
-
-func (ctx ExtensionValidationContext) HasChildNamed(name string) bool {
-	return len(ctx.ChildrenNamed(name)) > 0
-}
-
 func resetExtensionValidators() {
 	extensionValidatorsMu.Lock()
 	extensionValidators = nil
@@ -185,17 +168,17 @@ func universalAdIDExtensionValidator(ctx ExtensionValidationContext) *NodeAnalys
 
 	if len(nodes) == 0 {
 		analysis := ensureReport()
-		markFailure(analysis, "UniversalAdId extension must include at least one UniversalAdId node")
+		markFailure(analysis, ReasonMissingExtensionContent, "{expected} extension must include at least one {expected} node", "expected", "UniversalAdId")
 		return analysis
 	}
 
 	extType := ctx.Type()
 	if extType == "" {
 		analysis := ensureReport()
-		markFailure(analysis, "UniversalAdId extension should declare type=\"UniversalAdId\"")
+		markFailure(analysis, ReasonExtensionTypeMissing, `{expected} extension should declare type="{expected}"`, "expected", "UniversalAdId")
 	} else if !strings.EqualFold(extType, "UniversalAdId") {
 		analysis := ensureReport()
-		markWarning(analysis, "UniversalAdId extension type attribute value should be \"UniversalAdId\"")
+		markWarning(analysis, ReasonExtensionTypeMismatch, `{expected} extension type attribute value should be "{expected}"`, "type", extType, "expected", "UniversalAdId")
 	}
 
 	if report != nil && report.Status == StatusPass && len(report.Reasons) == 0 && len(report.Attributes) == 0 {
@@ -217,23 +200,23 @@ func interactiveCreativeFileExtensionValidator(ctx ExtensionValidationContext) *
 
 	if len(nodes) == 0 {
 		analysis := ensureReport()
-		markFailure(analysis, "InteractiveCreativeFile extension must include at least one InteractiveCreativeFile node")
+		markFailure(analysis, ReasonMissingExtensionContent, "{expected} extension must include at least one {expected} node", "expected", "InteractiveCreativeFile")
 		return analysis
 	}
 
 	extType := ctx.Type()
 	if extType == "" {
 		analysis := ensureReport()
-		markFailure(analysis, "InteractiveCreativeFile extension should declare type=\"InteractiveCreativeFile\"")
+		markFailure(analysis, ReasonExtensionTypeMissing, `{expected} extension should declare type="{expected}"`, "expected", "InteractiveCreativeFile")
 	} else if !strings.EqualFold(extType, "InteractiveCreativeFile") {
 		analysis := ensureReport()
-		markWarning(analysis, "InteractiveCreativeFile extension type attribute value should be \"InteractiveCreativeFile\"")
+		markWarning(analysis, ReasonExtensionTypeMismatch, `{expected} extension type attribute value should be "{expected}"`, "type", extType, "expected", "InteractiveCreativeFile")
 	}
 
 	for _, node := range nodes {
 		if strings.TrimSpace(node.Content) == "" {
 			analysis := ensureReport()
-			markFailure(analysis, "InteractiveCreativeFile must include executable content or a URL")
+			markFailure(analysis, ReasonMissingExtensionContent, "{node} must include executable content or a URL", "node", "InteractiveCreativeFile", "expected", "InteractiveCreativeFile")
 			break
 		}
 	}
@@ -257,23 +240,23 @@ func mezzanineExtensionValidator(ctx ExtensionValidationContext) *NodeAnalysisRe
 
 	if len(nodes) == 0 {
 		analysis := ensureReport()
-		markFailure(analysis, "Mezzanine extension must include at least one Mezzanine node")
+		markFailure(analysis, ReasonMissingExtensionContent, "{expected} extension must include at least one {expected} node", "expected", "Mezzanine")
 		return analysis
 	}
 
 	extType := ctx.Type()
 	if extType == "" {
 		analysis := ensureReport()
-		markFailure(analysis, "Mezzanine extension should declare type=\"Mezzanine\"")
+		markFailure(analysis, ReasonExtensionTypeMissing, `{expected} extension should declare type="{expected}"`, "expected", "Mezzanine")
 	} else if !strings.EqualFold(extType, "Mezzanine") {
 		analysis := ensureReport()
-		markWarning(analysis, "Mezzanine extension type attribute value should be \"Mezzanine\"")
+		markWarning(analysis, ReasonExtensionTypeMismatch, `{expected} extension type attribute value should be "{expected}"`, "type", extType, "expected", "Mezzanine")
 	}
 
 	for _, node := range nodes {
 		if strings.TrimSpace(node.Content) == "" {
 			analysis := ensureReport()
-			markFailure(analysis, "Mezzanine value must not be empty")
+			markFailure(analysis, ReasonMissingValue, "{node} value must not be empty", "node", "Mezzanine")
 			break
 		}
 	}
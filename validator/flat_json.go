@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FlatResult is one node/category pair of a validation result, as emitted by
+// MarshalFlatJSON.
+type FlatResult struct {
+	Path     string       `json:"path"`
+	Node     string       `json:"node"`
+	Category string       `json:"category"`
+	Status   ResultStatus `json:"status"`
+	Reasons  []Reason     `json:"reasons,omitempty"`
+}
+
+// MarshalFlatJSON encodes the result as a flat JSON array with one entry per
+// node and analysis category, in document order. The path is the node's source
+// pointer, which suits log and search systems better than the nested tree.
+func (r *ValidationResult) MarshalFlatJSON() ([]byte, error) {
+	if r == nil {
+		return nil, fmt.Errorf("validator: nil validation result")
+	}
+	entries := []FlatResult{}
+	var walk func(node *NodeResult)
+	walk = func(node *NodeResult) {
+		if node == nil {
+			return
+		}
+		path := flatPath(node)
+		for _, category := range sortedAnalysisCategories(node) {
+			analysis := node.Analyses[category]
+			entries = append(entries, FlatResult{
+				Path:     path,
+				Node:     node.Node,
+				Category: category,
+				Status:   analysis.Status,
+				Reasons:  analysis.Reasons,
+			})
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(r.Root)
+	return json.Marshal(entries)
+}
+
+// NDJSONNode is one node of a validation result, as emitted by WriteNDJSON.
+type NDJSONNode struct {
+	Path     string                         `json:"path"`
+	Node     string                         `json:"node"`
+	Analyses map[string]*NodeAnalysisResult `json:"analyses,omitempty"`
+}
+
+// WriteNDJSON writes the result to w as newline-delimited JSON with one object
+// per node, in document order, so large results can be processed line by line.
+// Children are not nested; the path is the node's source pointer.
+func (r *ValidationResult) WriteNDJSON(w io.Writer) error {
+	if r == nil {
+		return fmt.Errorf("validator: nil validation result")
+	}
+	encoder := json.NewEncoder(w)
+	var write func(node *NodeResult) error
+	write = func(node *NodeResult) error {
+		if node == nil {
+			return nil
+		}
+		if err := encoder.Encode(NDJSONNode{Path: flatPath(node), Node: node.Node, Analyses: node.Analyses}); err != nil {
+			return err
+		}
+		for _, child := range node.Children {
+			if err := write(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return write(r.Root)
+}
+
+// flatPath returns the node's source pointer, falling back to its name.
+func flatPath(node *NodeResult) string {
+	if node.SourcePointer == "" {
+		return node.Node
+	}
+	return node.SourcePointer
+}
@@ -1,9 +1,15 @@
 package validator
 
 import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -11,6 +17,15 @@ import (
 
 const probeRangeHeader = "bytes=0-0"
 
+// probeSniffLength is the number of decoded bytes inspected to detect a content
+// type, matching http.DetectContentType.
+const probeSniffLength = 512
+
+// defaultProbeBodyLimit is the number of raw response bytes a ranged GET probe
+// may read when HTTPValidationOptions.MaxProbeBodyBytes is unset. It leaves
+// room to decode probeSniffLength bytes from a compressed body.
+const defaultProbeBodyLimit = 4 << 10
+
 // probeMediaURL attempts to verify that the provided media URL responds to an
 // HTTP HEAD request. When a server disallows HEAD it falls back to an HTTP GET
 // with a byte range request to minimize transfer size.
@@ -20,7 +35,34 @@ func probeMediaURL(ctx context.Context, client *http.Client, rawURL string) (*ht
 		return nil, err
 	}
 
-	resp, err := doHTTPRequest(ctx, client, http.MethodHead, normalized, nil)
+	opts := httpOptionsFromContext(ctx)
+	clock := clockFromContext(ctx)
+	backoff := opts.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := probeMediaURLOnce(ctx, client, normalized)
+		if attempt >= opts.MaxRetries || !shouldRetryProbe(ctx, resp, err) {
+			return resp, err
+		}
+		if deadline, ok := ctx.Deadline(); ok && deadline.Sub(clock.Now()) <= backoff {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-clock.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+func probeMediaURLOnce(ctx context.Context, client *http.Client, target string) (*http.Response, error) {
+	// Ask for the identity encoding so HEAD metadata describes the media itself.
+	resp, err := doHTTPRequest(ctx, client, http.MethodHead, target, map[string]string{"Accept-Encoding": "identity"})
 	if err == nil {
 		if resp.StatusCode != http.StatusMethodNotAllowed {
 			return resp, nil
@@ -30,9 +72,134 @@ func probeMediaURL(ctx context.Context, client *http.Client, rawURL string) (*ht
 		return nil, err
 	}
 
-	// Fall back to a ranged GET request when HEAD is not supported.
-	headers := map[string]string{"Range": probeRangeHeader}
-	return doHTTPRequest(ctx, client, http.MethodGet, normalized, headers)
+	// Fall back to a ranged GET request when HEAD is not supported. Some CDNs
+	// compress regardless of the range, so accept and decode gzip/deflate.
+	headers := map[string]string{"Range": probeRangeHeader, "Accept-Encoding": "gzip, deflate"}
+	resp, err = doHTTPRequest(ctx, client, http.MethodGet, target, headers)
+	if err != nil {
+		return nil, err
+	}
+	// A server that ignores Range streams the whole file; never read past the cap.
+	resp.Body = &limitedBody{Reader: io.LimitReader(resp.Body, probeBodyLimit(ctx)), body: resp.Body}
+	decodeProbeResponse(resp)
+	return resp, nil
+}
+
+func probeBodyLimit(ctx context.Context) int64 {
+	if limit := httpOptionsFromContext(ctx).MaxProbeBodyBytes; limit > 0 {
+		return limit
+	}
+	return defaultProbeBodyLimit
+}
+
+// limitedBody caps reads from a response body while still closing it.
+type limitedBody struct {
+	io.Reader
+	body io.Closer
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+// decodeProbeResponse transparently decodes a gzip or deflate response body.
+// When the declared Content-Type is missing or only describes the compression,
+// the media type is detected from the decoded bytes instead.
+func decodeProbeResponse(resp *http.Response) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return
+	}
+	decoded, err := newContentDecoder(encoding, resp.Body)
+	if err != nil || decoded == nil {
+		return
+	}
+	sniff := make([]byte, probeSniffLength)
+	n, _ := io.ReadFull(decoded, sniff)
+	sniff = sniff[:n]
+
+	resp.Body = &decodedBody{Reader: io.MultiReader(bytes.NewReader(sniff), decoded), decoder: decoded, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+
+	if n > 0 && isEncodingContentType(resp.Header.Get("Content-Type")) {
+		resp.Header.Set("Content-Type", http.DetectContentType(sniff))
+	}
+}
+
+func newContentDecoder(encoding string, body io.Reader) (io.ReadCloser, error) {
+	switch encoding {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(body)
+	case "deflate":
+		// HTTP deflate is nominally zlib-wrapped, but raw deflate is common too.
+		buffered := bufio.NewReader(body)
+		header, err := buffered.Peek(2)
+		if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(buffered)
+		}
+		return flate.NewReader(buffered), nil
+	default:
+		return nil, nil
+	}
+}
+
+// isEncodingContentType reports whether a Content-Type is absent or describes
+// a compressed container rather than the underlying media.
+func isEncodingContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(contentType))
+	if idx := strings.Index(mediaType, ";"); idx >= 0 {
+		mediaType = strings.TrimSpace(mediaType[:idx])
+	}
+	switch mediaType {
+	case "", "application/gzip", "application/x-gzip", "application/zlib", "application/octet-stream":
+		return true
+	}
+	return false
+}
+
+// decodedBody closes both the decoder and the underlying response body.
+type decodedBody struct {
+	io.Reader
+	decoder io.Closer
+	body    io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	decoderErr := b.decoder.Close()
+	if err := b.body.Close(); err != nil {
+		return err
+	}
+	return decoderErr
+}
+
+// shouldRetryProbe reports whether a probe outcome is transient: a network
+// error (other than context cancellation) or a 5xx response.
+func shouldRetryProbe(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp != nil && resp.StatusCode >= http.StatusInternalServerError
+}
+
+type httpOptionsContextKey struct{}
+
+// contextWithHTTPOptions exposes the active HTTP options to built-in HTTP
+// validators, whose signature only carries a context and client.
+func contextWithHTTPOptions(ctx context.Context, opts HTTPValidationOptions) context.Context {
+	return context.WithValue(ctx, httpOptionsContextKey{}, opts)
+}
+
+func httpOptionsFromContext(ctx context.Context) HTTPValidationOptions {
+	if opts, ok := ctx.Value(httpOptionsContextKey{}).(HTTPValidationOptions); ok {
+		return opts
+	}
+	return HTTPValidationOptions{}
 }
 
 func normalizeProbeURL(raw string) (string, error) {
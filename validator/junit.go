@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit emits the validation result as a JUnit XML report so CI systems can
+// surface failures in their test dashboards. Each failing node/category pair becomes
+// a test case named after the node's source pointer; passing nodes are summarized as
+// a single test case per category.
+func (r *ValidationResult) WriteJUnit(w io.Writer, suiteName string) error {
+	if r == nil {
+		return fmt.Errorf("validator: nil validation result")
+	}
+	if suiteName == "" {
+		suiteName = "vast-validation"
+	}
+
+	suite := junitTestSuite{Name: suiteName}
+	passing := map[string]int{}
+	var walk func(node *NodeResult)
+	walk = func(node *NodeResult) {
+		if node == nil {
+			return
+		}
+		for _, category := range sortedAnalysisCategories(node) {
+			analysis := node.Analyses[category]
+			if analysis.Status != StatusFail {
+				passing[category]++
+				continue
+			}
+			message := ""
+			if len(analysis.Reasons) > 0 {
+				message = analysis.Reasons[0].Message
+			}
+			suite.TestCases = append(suite.TestCases, junitTestCase{
+				Name:      junitCaseName(node),
+				ClassName: category,
+				Failure: &junitFailure{
+					Message: message,
+					Type:    category,
+					Body:    strings.Join(reasonMessages(analysis.Reasons), "\n"),
+				},
+			})
+			suite.Failures++
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(r.Root)
+
+	categories := make([]string, 0, len(passing))
+	for category := range passing {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%s passing nodes", category),
+			ClassName: category,
+			SystemOut: fmt.Sprintf("%d node(s) passed %s", passing[category], category),
+		})
+	}
+	suite.Tests = len(suite.TestCases)
+
+	report := junitTestSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []junitTestSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("validator: encode JUnit report: %w", err)
+	}
+	return encoder.Flush()
+}
+
+func junitCaseName(node *NodeResult) string {
+	if node.SourcePointer != "" {
+		return node.SourcePointer
+	}
+	return node.Node
+}
+
+func sortedAnalysisCategories(node *NodeResult) []string {
+	categories := make([]string, 0, len(node.Analyses))
+	for category := range node.Analyses {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
@@ -2,36 +2,45 @@ package validator
 
 import (
 	"context"
-	"fmt"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
-func init() {
-	registerBuiltInHTTPValidators()
-}
-
-func registerBuiltInHTTPValidators() {
-	RegisterHTTPValidator("MediaFile", mediaFileHTTPValidator)
-}
+// defaultProbeNodes lists the nodes whose URL is probed unless WithProbeNodes
+// configures another set.
+var defaultProbeNodes = []string{"MediaFile"}
 
-func mediaFileHTTPValidator(ctx context.Context, nodeCtx NodeContext, client *http.Client) (*NodeAnalysisResult, error) {
+// resourceProbeValidator checks that the URL carried by a node such as
+// MediaFile, Mezzanine or StaticResource responds, matches its declared type and
+// roughly matches its declared fileSize.
+func resourceProbeValidator(ctx context.Context, nodeCtx NodeContext, client *http.Client) (*NodeAnalysisResult, error) {
+	name := nodeCtx.Node.localName()
 	url := nodeCtx.Text()
 	if url == "" {
-		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []string{"media file URL is empty"}}, nil
+		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []Reason{newReason(ReasonEmptyURL, "{node} URL is empty", "node", name)}}, nil
+	}
+
+	if opts := httpOptionsFromContext(ctx); opts.skipsProbe(url) {
+		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusInfo, Reasons: []Reason{newReason(ReasonProbeSkipped, "probe skipped for {url}", "url", url)}}, nil
 	}
 
 	resp, err := probeMediaURL(ctx, client, url)
 	if err != nil {
-		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []string{fmt.Sprintf("media file request failed: %v", err)}}, nil
+		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []Reason{newReason(ReasonProbeFailed, "{node} request failed: {error}", "node", name, "error", err)}}, nil
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []string{fmt.Sprintf("media file responded with HTTP %d", resp.StatusCode)}}, nil
+		return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []Reason{newReason(ReasonProbeFailed, "{node} responded with HTTP {status}", "node", name, "status", resp.StatusCode)}}, nil
 	}
 
-	if expected, ok := nodeCtx.Attribute("type"); ok {
+	expected, ok := nodeCtx.Attribute("type")
+	if !ok {
+		expected, ok = nodeCtx.Attribute("creativeType")
+	}
+	if ok {
 		expected = strings.ToLower(strings.TrimSpace(expected))
 		if expected != "" {
 			actual := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Type")))
@@ -39,10 +48,57 @@ func mediaFileHTTPValidator(ctx context.Context, nodeCtx NodeContext, client *ht
 				actual = strings.TrimSpace(actual[:idx])
 			}
 			if actual != "" && actual != expected {
-				return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []string{fmt.Sprintf("content type mismatch: expected %s, got %s", expected, actual)}}, nil
+				return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusFail, Reasons: []Reason{newReason(ReasonContentTypeMismatch, "content type mismatch: expected {expected}, got {value}", "expected", expected, "value", actual)}}, nil
 			}
 		}
 	}
 
-	return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusPass}, nil
+	analysis := &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusPass}
+	checkDeclaredFileSize(analysis, nodeCtx, resp)
+	return analysis, nil
+}
+
+// fileSizeTolerance is the relative difference between the declared fileSize and
+// the size reported by the server above which a warning is raised.
+const fileSizeTolerance = 0.1
+
+// checkDeclaredFileSize warns when the declared fileSize differs significantly
+// from the size reported by the server. It does nothing unless both are known.
+func checkDeclaredFileSize(analysis *NodeAnalysisResult, nodeCtx NodeContext, resp *http.Response) {
+	raw, ok := nodeCtx.Attribute("fileSize")
+	if !ok {
+		return
+	}
+	declared, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || declared <= 0 {
+		return
+	}
+	actual, ok := responseContentLength(resp)
+	if !ok {
+		return
+	}
+	if math.Abs(float64(actual-declared)) > float64(declared)*fileSizeTolerance {
+		markWarning(analysis, ReasonFileSizeMismatch, "declared fileSize {expected} differs from server-reported size {value}", "expected", declared, "value", actual)
+	}
+}
+
+// responseContentLength returns the full size of the probed resource, taken from
+// the Content-Range total of a partial response or the Content-Length otherwise.
+func responseContentLength(resp *http.Response) (int64, bool) {
+	if resp.StatusCode == http.StatusPartialContent {
+		contentRange := resp.Header.Get("Content-Range")
+		idx := strings.LastIndex(contentRange, "/")
+		if idx < 0 {
+			return 0, false
+		}
+		total, err := strconv.ParseInt(strings.TrimSpace(contentRange[idx+1:]), 10, 64)
+		if err != nil || total <= 0 {
+			return 0, false
+		}
+		return total, true
+	}
+	if resp.ContentLength > 0 {
+		return resp.ContentLength, true
+	}
+	return 0, false
 }
@@ -0,0 +1,127 @@
+package validator
+
+import "maps"
+
+// defaultMessages is the English message catalog keyed by reason code. Rules
+// report a detailed English message of their own; the catalog holds the generic
+// wording that WithMessages translations replace it with. A placeholder such as
+// {attribute} appears only where every rule reporting the code supplies it.
+var defaultMessages = map[string]string{
+	ReasonUnsupportedVersion:       "The {node} version {version} is not supported.",
+	ReasonInvalidVersionFormat:     "The {node} version {version} is not a major.minor number.",
+	ReasonInformationalOnly:        "Validation of this document is informational only.",
+	ReasonUnknownNode:              "Node {node} is not recognized.",
+	ReasonVendorNode:               "Node {node} is validated by a vendor catalog ({categories}).",
+	ReasonInvalidCasing:            "Node {node} uses invalid casing; use {expected}.",
+	ReasonUnsupportedNode:          "Node {node} is not supported in VAST {version}.",
+	ReasonInvalidChild:             "Node {node} is not a valid child of {parent}.",
+	ReasonRepeatedChild:            "Node {node} repeats {child}, which is allowed only once.",
+	ReasonMissingValue:             "Node {node} requires a value.",
+	ReasonEscapedText:              "Node {node} must wrap its value in CDATA.",
+	ReasonExtensionTypeMissing:     "The extension should declare type {expected}.",
+	ReasonExtensionTypeMismatch:    "The extension type {type} does not match {expected}.",
+	ReasonMissingExtensionContent:  "The {expected} extension is missing its content.",
+	ReasonUnknownAttribute:         "Node {node} has an unknown attribute {attribute}.",
+	ReasonUnsupportedAttribute:     "Node {node} has attribute {attribute}, which is not supported in VAST {version}.",
+	ReasonEmptyAttribute:           "Node {node} has an empty attribute {attribute}.",
+	ReasonInvalidAttributeValue:    "Node {node} has an invalid value {value} for attribute {attribute}.",
+	ReasonUnknownAttributeValue:    "Node {node} has an unrecognized value {value} for attribute {attribute}.",
+	ReasonMissingRequiredAttribute: "Node {node} is missing required attribute {attribute}.",
+	ReasonDuplicateAttribute:       "Node {node} repeats attribute {attribute}.",
+	ReasonVerbose:                  "Node {node} passed validation.",
+	ReasonVersionMismatch:          "The VAST {version} document uses features from a later version: {features}.",
+	ReasonInvalidAdType:            "The Ad must contain exactly one InLine or Wrapper.",
+	ReasonInvalidCreative:          "The Creative must contain exactly one creative type.",
+	ReasonInlineOnlyNode:           "{child} belongs to an InLine ad and must not appear under Wrapper.",
+	ReasonMissingMediaFile:         "MediaFiles must contain at least one MediaFile.",
+	ReasonInvalidDuration:          "The duration {value} is invalid.",
+	ReasonInvalidTrackingOffset:    "The Tracking offset is invalid for event {event}.",
+	ReasonDuplicateTrackingID:      "The {child} id {id} is used more than once.",
+	ReasonDuplicateAdID:            "The Ad id {id} is used more than once.",
+	ReasonTooManyAds:               "The document contains {count} ads; at most {limit} are allowed.",
+	ReasonMissingCompanion:         "CompanionAds requires Companion elements.",
+	ReasonMissingAltText:           "The Companion should include AltText.",
+	ReasonMissingResource:          "Node {node} has no resource.",
+	ReasonMultipleResources:        "Node {node} has more than one resource type: {found}.",
+	ReasonIncompleteIconLayout:     "The Icon should declare its size and position; missing {missing}.",
+	ReasonInvalidCreativeType:      "Node {node} declares an invalid creative type.",
+	ReasonInvalidMezzanineType:     "The Mezzanine type {value} is not a mezzanine format.",
+	ReasonInvalidCaptionType:       "The ClosedCaptionFile type {value} is not a caption format.",
+	ReasonInvalidLanguageTag:       "Node {node} declares an invalid language tag {value}.",
+	ReasonInvalidMIMEType:          "Node {node} declares type {value}, which is not a MIME type.",
+	ReasonDeprecatedNode:           "Node {node} is deprecated in VAST {version}.",
+	ReasonInvalidCategory:          "Node {node} has an invalid category.",
+	ReasonInvalidAPIFramework:      "Node {node} declares an invalid apiFramework.",
+	ReasonVPAID:                    "Node {node} uses VPAID.",
+	ReasonInvalidNamespace:         "The VAST namespace declaration is invalid; expected {expected}.",
+	ReasonEmptyURL:                 "Node {node} has an empty URL.",
+	ReasonMissingViewableURL:       "ViewableImpression should contain a URL.",
+	ReasonMissingClickThrough:      "Node {node} tracks clicks without a click-through URL.",
+	ReasonUnreplacedMacro:          "Node {node} contains an unreplaced macro [{macro}].",
+	ReasonMissingErrorCodeMacro:    "The Error URL should include the [ERRORCODE] macro.",
+	ReasonInsecureURL:              "The URL {url} of {node} should use https.",
+	ReasonAdSystemAudit:            "AdSystem {name} version audit.",
+	ReasonMissingAdSystem:          "AdSystem should name the ad server.",
+	ReasonMissingAdServingID:       "The InLine ad should include an AdServingId.",
+	ReasonProbeSkipped:             "The URL probe of {url} was skipped.",
+	ReasonProbeFailed:              "The URL of {node} could not be fetched.",
+	ReasonContentTypeMismatch:      "The URL of {node} returned content type {value} instead of {expected}.",
+	ReasonFileSizeMismatch:         "The declared fileSize {expected} of {node} does not match the server size {value}.",
+	ReasonOverallTimeout:           "Skipped due to the overall timeout.",
+	ReasonCanceled:                 "Skipped because validation was cancelled.",
+	ReasonValidatorError:           "A validator returned an error: {error}.",
+}
+
+// DefaultMessages returns a copy of the English message catalog keyed by reason
+// code, as a starting point for WithMessages translations.
+func DefaultMessages() map[string]string {
+	return maps.Clone(defaultMessages)
+}
+
+// WithMessages replaces the message of every reason whose code appears in
+// messages, for example to serve translated results. Placeholders such as
+// "{attribute}" or "{value}" are replaced with the details the rule reported,
+// and "{node}" with the name of the reporting node; DefaultMessages shows the
+// placeholders each code supplies. Codes not listed keep the detailed English
+// message.
+func WithMessages(messages map[string]string) Option {
+	return func(cfg *config) {
+		cfg.messages = make(map[string]string, len(messages))
+		for code, message := range messages {
+			cfg.messages[code] = message
+		}
+	}
+}
+
+// localizeReasons rewrites reason messages in the tree using messages.
+func localizeReasons(root *NodeResult, messages map[string]string) {
+	if root == nil || len(messages) == 0 {
+		return
+	}
+	localize := func(reasons []Reason, node string) {
+		for i, reason := range reasons {
+			message, ok := messages[reason.Code]
+			if !ok {
+				continue
+			}
+			args := reason.args
+			if _, ok := args["node"]; !ok {
+				args = maps.Clone(args)
+				if args == nil {
+					args = map[string]string{}
+				}
+				args["node"] = node
+			}
+			reasons[i].Message = formatMessage(message, args)
+		}
+	}
+	for _, analysis := range root.Analyses {
+		localize(analysis.Reasons, root.Node)
+		for i := range analysis.Attributes {
+			localize(analysis.Attributes[i].Reasons, root.Node)
+		}
+	}
+	for _, child := range root.Children {
+		localizeReasons(child, messages)
+	}
+}
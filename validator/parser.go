@@ -2,6 +2,7 @@ package validator
 
 import (
 	"bytes"
+	"compress/gzip"
 	"encoding/xml"
 	"errors"
 	"fmt"
@@ -17,28 +18,147 @@ type genericNode struct {
 	Attrs    []xml.Attr
 	Children []*genericNode
 	Content  string
+	Parent   *genericNode
+	// Raw is the element's source XML, sharing the parsed document's buffer.
+	Raw []byte
+	// EscapedText is set when text outside CDATA used entity references such
+	// as &amp;.
+	EscapedText bool
+	// released is set by ValidateStream once the subtree has been validated,
+	// reported and dropped; only the element and its attributes remain.
+	released bool
 }
 
 func (n *genericNode) localName() string {
 	return n.Name.Local
 }
 
+const (
+	// xmlNamespaceURL is the namespace encoding/xml assigns to the reserved xml prefix.
+	xmlNamespaceURL = "http://www.w3.org/XML/1998/namespace"
+	// xsiNamespaceURL is the XML Schema instance namespace conventionally bound to xsi.
+	xsiNamespaceURL = "http://www.w3.org/2001/XMLSchema-instance"
+	// vastNamespaceURL is the target namespace of the IAB VAST 4.x schema.
+	vastNamespaceURL = "http://www.iab.com/VAST"
+)
+
+// wellKnownNamespaces maps conventional prefixes to their namespace URIs so
+// attributes resolve the same whether or not the document declares the prefix.
+var wellKnownNamespaces = map[string]string{
+	"xml": xmlNamespaceURL,
+	"xsi": xsiNamespaceURL,
+}
+
+// attrValue looks up an attribute by name. Unprefixed names only match
+// attributes without a namespace, so xsi:type never answers for type; prefixed
+// names such as "xml:lang" match the attribute in that namespace.
 func (n *genericNode) attrValue(name string) (string, bool) {
+	prefix, local, prefixed := strings.Cut(name, ":")
 	for _, attr := range n.Attrs {
-		if attr.Name.Local == name {
+		if prefixed {
+			if attr.Name.Local == local && attr.Name.Space != "" && namespaceMatches(attr.Name.Space, namespaceForPrefix(prefix)) {
+				return attr.Value, true
+			}
+			continue
+		}
+		if attr.Name.Space == "" && attr.Name.Local == name {
 			return attr.Value, true
 		}
 	}
 	return "", false
 }
 
+// namespaceForPrefix returns the URI conventionally bound to prefix, or the
+// prefix itself when it is not well known.
+func namespaceForPrefix(prefix string) string {
+	if uri, ok := wellKnownNamespaces[prefix]; ok {
+		return uri
+	}
+	return prefix
+}
+
+// namespaceMatches reports whether an attribute namespace, as reported by
+// encoding/xml (a URI, or the raw prefix when undeclared), satisfies the
+// namespace a spec requires. An empty spec namespace only matches plain attributes.
+func namespaceMatches(attrSpace, specNamespace string) bool {
+	if specNamespace == "" {
+		return attrSpace == ""
+	}
+	return attrSpace == specNamespace || namespaceForPrefix(attrSpace) == specNamespace
+}
+
+// qualifiedAttrName returns the catalog name of an attribute: the local name for
+// plain attributes, or "prefix:local" for namespaced ones using the conventional
+// prefix when the namespace is well known.
+func qualifiedAttrName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	for prefix, uri := range wellKnownNamespaces {
+		if name.Space == uri || name.Space == prefix {
+			return prefix + ":" + name.Local
+		}
+	}
+	return name.Space + ":" + name.Local
+}
+
+// walk visits the node's descendants depth-first in document order. Returning
+// false from fn skips the visited node's subtree.
+func (n *genericNode) walk(fn func(node *genericNode) bool) {
+	for _, child := range n.Children {
+		if fn(child) {
+			child.walk(fn)
+		}
+	}
+}
+
+// utf8BOM is the byte order mark some ad servers prepend to UTF-8 documents.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// trimDocumentPrefix drops a leading UTF-8 BOM and any whitespace before the XML
+// declaration or root element.
+func trimDocumentPrefix(raw []byte) []byte {
+	return bytes.TrimLeft(bytes.TrimPrefix(raw, utf8BOM), " \t\r\n")
+}
+
+// gzipMagic is the header every gzip stream starts with. It cannot begin an XML
+// document, so plain input is never mistaken for gzip.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// maxDecompressedSize bounds how far a gzip-compressed document may expand.
+const maxDecompressedSize = 32 << 20
+
+// decompressDocument returns raw unchanged unless it is gzip-compressed, in which
+// case it returns the decompressed document.
+func decompressDocument(raw []byte) ([]byte, error) {
+	if !bytes.HasPrefix(raw, gzipMagic) {
+		return raw, nil
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("validator: gzip: %w", err)
+	}
+	defer reader.Close()
+	decompressed, err := io.ReadAll(io.LimitReader(reader, maxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("validator: gzip: %w", err)
+	}
+	if len(decompressed) > maxDecompressedSize {
+		return nil, fmt.Errorf("validator: gzip: document exceeds %d bytes", maxDecompressedSize)
+	}
+	return decompressed, nil
+}
+
 // buildNodeTree parses raw XML bytes into a tree of genericNode instances.
 func buildNodeTree(raw []byte) (*genericNode, error) {
-	decoder := xml.NewDecoder(bytes.NewReader(raw))
+	source := trimDocumentPrefix(raw)
+	decoder := xml.NewDecoder(bytes.NewReader(source))
 	var stack []*genericNode
+	var starts []int64
 	var root *genericNode
 
 	for {
+		offset := decoder.InputOffset()
 		token, err := decoder.Token()
 		if err != nil {
 			if errors.Is(err, io.EOF) {
@@ -54,29 +174,25 @@ func buildNodeTree(raw []byte) (*genericNode, error) {
 				root = node
 			} else {
 				parent := stack[len(stack)-1]
+				node.Parent = parent
 				parent.Children = append(parent.Children, node)
 			}
 			stack = append(stack, node)
+			starts = append(starts, offset)
 
 		case xml.EndElement:
 			if len(stack) == 0 {
 				return nil, fmt.Errorf("validator: unexpected closing tag %q", typed.Name.Local)
 			}
+			stack[len(stack)-1].Raw = source[starts[len(starts)-1]:decoder.InputOffset()]
 			stack = stack[:len(stack)-1]
+			starts = starts[:len(starts)-1]
 
 		case xml.CharData:
 			if len(stack) == 0 {
 				continue
 			}
-			trimmed := strings.TrimSpace(string(typed))
-			if trimmed == "" {
-				continue
-			}
-			current := stack[len(stack)-1]
-			if current.Content != "" {
-				current.Content += " "
-			}
-			current.Content += trimmed
+			appendText(stack[len(stack)-1], typed, source[offset:decoder.InputOffset()])
 		}
 	}
 
@@ -86,3 +202,19 @@ func buildNodeTree(raw []byte) (*genericNode, error) {
 
 	return root, nil
 }
+
+// appendText adds the trimmed character data of a token to node. segment is
+// the token's source text, used to tell escaped text from CDATA.
+func appendText(node *genericNode, text xml.CharData, segment []byte) {
+	trimmed := strings.TrimSpace(string(text))
+	if trimmed == "" {
+		return
+	}
+	if node.Content != "" {
+		node.Content += " "
+	}
+	node.Content += trimmed
+	if !bytes.HasPrefix(segment, []byte("<![CDATA[")) && bytes.Contains(segment, []byte("&")) {
+		node.EscapedText = true
+	}
+}
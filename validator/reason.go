@@ -0,0 +1,150 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Reason is a single validation finding. Code is a stable identifier tooling can
+// branch on; Message is the human-readable explanation.
+type Reason struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+
+	// args holds the values of the message placeholders, such as {attribute},
+	// so WithMessages translations can repeat them.
+	args map[string]string
+}
+
+// String returns the reason message.
+func (r Reason) String() string {
+	return r.Message
+}
+
+// Reason codes reported by the catalog checks.
+const (
+	ReasonUnsupportedVersion       = "UNSUPPORTED_VERSION"
+	ReasonInvalidVersionFormat     = "INVALID_VERSION_FORMAT"
+	ReasonInformationalOnly        = "INFORMATIONAL_ONLY"
+	ReasonUnknownNode              = "UNKNOWN_NODE"
+	ReasonVendorNode               = "VENDOR_NODE"
+	ReasonInvalidCasing            = "INVALID_CASING"
+	ReasonUnsupportedNode          = "UNSUPPORTED_NODE"
+	ReasonInvalidChild             = "INVALID_CHILD"
+	ReasonRepeatedChild            = "REPEATED_CHILD"
+	ReasonMissingValue             = "MISSING_VALUE"
+	ReasonEscapedText              = "ESCAPED_TEXT"
+	ReasonExtensionTypeMissing     = "EXTENSION_TYPE_MISSING"
+	ReasonExtensionTypeMismatch    = "EXTENSION_TYPE_MISMATCH"
+	ReasonMissingExtensionContent  = "MISSING_EXTENSION_CONTENT"
+	ReasonUnknownAttribute         = "UNKNOWN_ATTRIBUTE"
+	ReasonUnsupportedAttribute     = "UNSUPPORTED_ATTRIBUTE"
+	ReasonEmptyAttribute           = "EMPTY_ATTRIBUTE"
+	ReasonInvalidAttributeValue    = "INVALID_ATTRIBUTE_VALUE"
+	ReasonUnknownAttributeValue    = "UNKNOWN_ATTRIBUTE_VALUE"
+	ReasonMissingRequiredAttribute = "MISSING_REQUIRED_ATTRIBUTE"
+	ReasonDuplicateAttribute       = "DUPLICATE_ATTRIBUTE"
+	ReasonVerbose                  = "VERBOSE"
+	ReasonVersionMismatch          = "VERSION_MISMATCH"
+)
+
+// Reason codes reported by the built-in rules.
+const (
+	ReasonInvalidAdType         = "INVALID_AD_TYPE"
+	ReasonInvalidCreative       = "INVALID_CREATIVE"
+	ReasonInlineOnlyNode        = "INLINE_ONLY_NODE"
+	ReasonMissingMediaFile      = "MISSING_MEDIA_FILE"
+	ReasonInvalidDuration       = "INVALID_DURATION"
+	ReasonInvalidTrackingOffset = "INVALID_TRACKING_OFFSET"
+	ReasonDuplicateTrackingID   = "DUPLICATE_TRACKING_ID"
+	ReasonDuplicateAdID         = "DUPLICATE_AD_ID"
+	ReasonTooManyAds            = "TOO_MANY_ADS"
+	ReasonMissingCompanion      = "MISSING_COMPANION"
+	ReasonMissingAltText        = "MISSING_ALT_TEXT"
+	ReasonMissingResource       = "MISSING_RESOURCE"
+	ReasonMultipleResources     = "MULTIPLE_RESOURCES"
+	ReasonIncompleteIconLayout  = "INCOMPLETE_ICON_LAYOUT"
+	ReasonInvalidCreativeType   = "INVALID_CREATIVE_TYPE"
+	ReasonInvalidMezzanineType  = "INVALID_MEZZANINE_TYPE"
+	ReasonInvalidCaptionType    = "INVALID_CAPTION_TYPE"
+	ReasonInvalidLanguageTag    = "INVALID_LANGUAGE_TAG"
+	ReasonInvalidMIMEType       = "INVALID_MIME_TYPE"
+	ReasonDeprecatedNode        = "DEPRECATED_NODE"
+	ReasonInvalidCategory       = "INVALID_CATEGORY"
+	ReasonInvalidAPIFramework   = "INVALID_API_FRAMEWORK"
+	ReasonVPAID                 = "VPAID"
+	ReasonInvalidNamespace      = "INVALID_NAMESPACE"
+	ReasonEmptyURL              = "EMPTY_URL"
+	ReasonMissingViewableURL    = "MISSING_VIEWABLE_URL"
+	ReasonMissingClickThrough   = "MISSING_CLICK_THROUGH"
+	ReasonUnreplacedMacro       = "UNREPLACED_MACRO"
+	ReasonMissingErrorCodeMacro = "MISSING_ERRORCODE_MACRO"
+	ReasonInsecureURL           = "INSECURE_URL"
+	ReasonAdSystemAudit         = "AD_SYSTEM_AUDIT"
+	ReasonMissingAdSystem       = "MISSING_AD_SYSTEM"
+	ReasonMissingAdServingID    = "MISSING_AD_SERVING_ID"
+)
+
+// Reason codes reported by HTTP validators.
+const (
+	ReasonProbeSkipped        = "PROBE_SKIPPED"
+	ReasonProbeFailed         = "PROBE_FAILED"
+	ReasonContentTypeMismatch = "CONTENT_TYPE_MISMATCH"
+	ReasonFileSizeMismatch    = "FILE_SIZE_MISMATCH"
+	ReasonOverallTimeout      = "OVERALL_TIMEOUT"
+	ReasonCanceled            = "CANCELED"
+	ReasonValidatorError      = "VALIDATOR_ERROR"
+)
+
+// newReason returns a reason whose message is the template message with each
+// {name} placeholder replaced by its value in args, which alternates names and
+// values: newReason(code, "attribute {attribute} cannot be empty", "attribute",
+// name).
+func newReason(code, message string, args ...any) Reason {
+	reason := Reason{Code: code}
+	if len(args) > 0 {
+		reason.args = make(map[string]string, len(args)/2)
+		for i := 0; i+1 < len(args); i += 2 {
+			reason.args[fmt.Sprint(args[i])] = fmt.Sprint(args[i+1])
+		}
+	}
+	reason.Message = formatMessage(message, reason.args)
+	return reason
+}
+
+// formatMessage replaces each {name} placeholder in message with args[name].
+// Placeholders without a value are kept as written.
+func formatMessage(message string, args map[string]string) string {
+	if len(args) == 0 || !strings.Contains(message, "{") {
+		return message
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(message, '{')
+		if open < 0 {
+			break
+		}
+		length := strings.IndexByte(message[open:], '}')
+		if length < 0 {
+			break
+		}
+		value, ok := args[message[open+1:open+length]]
+		if !ok {
+			value = message[open : open+length+1]
+		}
+		b.WriteString(message[:open])
+		b.WriteString(value)
+		message = message[open+length+1:]
+	}
+	b.WriteString(message)
+	return b.String()
+}
+
+// reasonMessages returns the messages of reasons in order.
+func reasonMessages(reasons []Reason) []string {
+	messages := make([]string, 0, len(reasons))
+	for _, reason := range reasons {
+		messages = append(messages, reason.Message)
+	}
+	return messages
+}
@@ -45,17 +45,18 @@ func statusSeverity(status ResultStatus) int {
 // AttributeResult captures the outcome of validating a single attribute.
 type AttributeResult struct {
 	Name           string         `json:"name"`
+	Namespace      string         `json:"namespace,omitempty"` // Namespace URI for prefixed attributes such as xml:lang.
 	Value          string         `json:"value,omitempty"`
 	IntroducedAt   *float64       `json:"introducedAt"`
 	AllowedValues  []string       `json:"allowedValues,omitempty"`
 	VersionSupport []vast.Version `json:"versionSupport,omitempty"`
 	Status         ResultStatus   `json:"status"`
-	Reasons        []string       `json:"reason,omitempty"`
+	Reasons        []Reason       `json:"reason,omitempty"`
 }
 
 // addReason ensures reason slices stay non-nil before serialization.
-func (ar *AttributeResult) addReason(reason string) {
-	if reason == "" {
+func (ar *AttributeResult) addReason(reason Reason) {
+	if reason.Message == "" {
 		return
 	}
 	ar.Reasons = append(ar.Reasons, reason)
@@ -66,8 +67,9 @@ func (ar *AttributeResult) addReason(reason string) {
 type NodeAnalysisResult struct {
 	Category   string            `json:"category"`
 	Status     ResultStatus      `json:"status"`
-	Reasons    []string          `json:"reason,omitempty"`
+	Reasons    []Reason          `json:"reason,omitempty"`
 	Attributes []AttributeResult `json:"attributes,omitempty"`
+	DurationMs float64           `json:"durationMs,omitempty"` // Populated for HTTP validators when timings are recorded.
 }
 
 // addAttribute appends an attribute result to the analysis bucket.
@@ -84,6 +86,8 @@ type NodeResult struct {
 	VersionSupport []vast.Version                 `json:"versionSupport,omitempty"`
 	Analyses       map[string]*NodeAnalysisResult `json:"analyses,omitempty"`
 	Children       []*NodeResult                  `json:"children,omitempty"`
+	// Snippet is the node's source XML, set on failing nodes when WithSnippets is used.
+	Snippet string `json:"snippet,omitempty"`
 }
 
 // addAnalysis ensures there is an analysis bucket for the given category and returns it.
@@ -104,6 +108,40 @@ type ValidationResult struct {
 	Version   vast.Version                `json:"version"`
 	Root      *NodeResult                 `json:"root"`
 	Summaries map[string]*CategorySummary `json:"summaries,omitempty"`
+	// HTTPDurationMs is the total time spent in HTTP validators when timings are recorded.
+	HTTPDurationMs float64 `json:"httpDurationMs,omitempty"`
+}
+
+// OverallStatus reduces the category summaries to a single status suitable for
+// exit codes: fail when any category fails, warning when only warnings exist,
+// and pass otherwise. Summaries are computed from the tree when the result has
+// none, as with WithStructuralOnly.
+func (r *ValidationResult) OverallStatus() ResultStatus {
+	if r == nil {
+		return StatusPass
+	}
+	summaries := r.Summaries
+	if summaries == nil {
+		summaries = summarizeCategories(r.Root)
+	}
+	status := StatusPass
+	for _, summary := range summaries {
+		if summary == nil {
+			continue
+		}
+		switch summary.Status {
+		case StatusFail:
+			return StatusFail
+		case StatusWarning:
+			status = StatusWarning
+		}
+	}
+	return status
+}
+
+// HasFailures reports whether any analysis category recorded a failure.
+func (r *ValidationResult) HasFailures() bool {
+	return r.OverallStatus() == StatusFail
 }
 
 // CategorySummary aggregates node results per analysis category for quick UI consumption.
@@ -114,7 +152,7 @@ type CategorySummary struct {
 	WarningNodes        int          `json:"warningNodes,omitempty"`
 	RecommendationNodes int          `json:"recommendationNodes,omitempty"`
 	Status              ResultStatus `json:"status"`
-	Reasons             []string     `json:"reasons,omitempty"`
+	Reasons             []Reason     `json:"reasons,omitempty"`
 }
 
 func summarizeCategories(root *NodeResult) map[string]*CategorySummary {
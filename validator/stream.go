@@ -0,0 +1,222 @@
+package validator
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ValidateStream validates the document read from r like Validate, but instead
+// of building a ValidationResult it passes each analysis of every node to
+// onResult, together with the node's source pointer. The document is decoded
+// incrementally: each child of the root, such as an Ad of a pod, is validated
+// and reported as soon as its end tag is read and its parsed elements are then
+// released, so memory is bounded by the largest child rather than the whole
+// document. Children are reported before their parent; the root is reported
+// last and its own checks only see the attributes of its released children.
+//
+// Summaries, HTTP timings, snippets and WithVersionMismatchSummary are not
+// available in this mode. Under WithFailFast decoding stops after the first
+// failing child and the root is not reported. Errors that prevent validation
+// are returned as *ValidateError; a parse error may follow callbacks for the
+// children decoded before it.
+func ValidateStream(r io.Reader, onResult func(path string, res *NodeAnalysisResult), opts ...Option) (err error) {
+	if onResult == nil {
+		return errors.New("validator: ValidateStream requires an onResult callback")
+	}
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = newValidateError(InternalError, fmt.Errorf("validator: panic during validation: %v", recovered))
+		}
+	}()
+
+	input, err := streamInput(r)
+	if err != nil {
+		return newValidateError(ParseError, err)
+	}
+	cfg := newConfig(opts...)
+	cfg.onResult = onResult
+	return streamDocument(input, cfg)
+}
+
+// streamInput decompresses a gzip-compressed stream and drops a leading UTF-8
+// BOM and whitespace, like decompressDocument and trimDocumentPrefix.
+func streamInput(r io.Reader) (*bufio.Reader, error) {
+	input := bufio.NewReader(r)
+	if magic, _ := input.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		decompressed, err := gzip.NewReader(input)
+		if err != nil {
+			return nil, fmt.Errorf("validator: gzip: %w", err)
+		}
+		input = bufio.NewReader(&cappedReader{r: decompressed, remaining: maxDecompressedSize})
+	}
+	if bom, _ := input.Peek(len(utf8BOM)); bytes.Equal(bom, utf8BOM) {
+		input.Discard(len(utf8BOM))
+	}
+	for {
+		next, err := input.Peek(1)
+		if err != nil || !bytes.ContainsAny(next, " \t\r\n") {
+			return input, nil
+		}
+		input.Discard(1)
+	}
+}
+
+// cappedReader fails once more than maxDecompressedSize bytes were read.
+type cappedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.remaining < 0 {
+		return 0, fmt.Errorf("validator: gzip: document exceeds %d bytes", maxDecompressedSize)
+	}
+	if int64(len(p)) > c.remaining+1 {
+		p = p[:c.remaining+1]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	return n, err
+}
+
+// streamDocument decodes the document token by token, validating and releasing
+// each child of the root when it closes and the root itself last.
+func streamDocument(input *bufio.Reader, cfg *config) error {
+	source := &sourceRecorder{r: input}
+	decoder := xml.NewDecoder(source)
+	var (
+		stack       []*genericNode
+		doc         *documentRoot
+		rootPointer string
+		occurrences = map[string]int{}
+
+		childAllowsUnknown   bool
+		extensionType        string
+		inBackportSubtree    bool
+		inExtensionContainer bool
+	)
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return newValidateError(ParseError, fmt.Errorf("validator: parse XML: %w", err))
+		}
+
+		switch typed := token.(type) {
+		case xml.StartElement:
+			node := &genericNode{Name: typed.Name, Attrs: typed.Attr}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				node.Parent = parent
+				parent.Children = append(parent.Children, node)
+			} else {
+				if doc != nil {
+					return newValidateError(ParseError, fmt.Errorf("validator: unexpected second root element %q", typed.Name.Local))
+				}
+				if doc, err = resolveRoot(node, cfg); err != nil {
+					return err
+				}
+				defer startOverallTimeout(cfg)()
+				rootPointer = buildSourcePointer("", doc.name, 1)
+				childAllowsUnknown = doc.spec.AllowUnknownChildren
+				extensionType, inBackportSubtree, inExtensionContainer = extensionState(node, doc.spec, "", false, false)
+			}
+			stack = append(stack, node)
+
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return newValidateError(ParseError, fmt.Errorf("validator: unexpected closing tag %q", typed.Name.Local))
+			}
+			closed := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch len(stack) {
+			case 0:
+				rootResult := validateNodeRecursive(closed, doc.version, cfg, doc.spec, nil, false, "", false, false, rootPointer)
+				finishRoot(rootResult, doc, cfg)
+				emitResult(rootResult, cfg)
+			case 1:
+				name := closed.localName()
+				occurrences[name]++
+				childSpec := childSpecFor(cfg.catalog, doc.spec, name)
+				pointer := buildSourcePointer(rootPointer, name, occurrences[name])
+				childResult := validateNodeRecursive(closed, doc.version, cfg, childSpec, doc.spec, childAllowsUnknown, extensionType, inBackportSubtree, inExtensionContainer, pointer)
+				emitResult(childResult, cfg)
+				closed.Children = nil
+				closed.released = true
+				if cfg.halted {
+					return nil
+				}
+			}
+
+		case xml.CharData:
+			if len(stack) > 0 {
+				appendText(stack[len(stack)-1], typed, source.segment(start, decoder.InputOffset()))
+			}
+		}
+		source.discard(decoder.InputOffset())
+	}
+
+	if doc == nil {
+		return newValidateError(ParseError, errEmptyXML)
+	}
+	return nil
+}
+
+// sourceRecorder keeps the bytes the XML decoder has read but not yet
+// discarded, so a token's source text can be inspected without buffering the
+// whole document. It implements io.ByteReader so the decoder reads exactly the
+// bytes it consumes.
+type sourceRecorder struct {
+	r   *bufio.Reader
+	buf []byte
+	// base is the input offset of buf[0].
+	base int64
+}
+
+func (s *sourceRecorder) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	s.buf = append(s.buf, p[:n]...)
+	return n, err
+}
+
+func (s *sourceRecorder) ReadByte() (byte, error) {
+	b, err := s.r.ReadByte()
+	if err == nil {
+		s.buf = append(s.buf, b)
+	}
+	return b, err
+}
+
+// segment returns the source between the input offsets start and end.
+func (s *sourceRecorder) segment(start, end int64) []byte {
+	return s.buf[start-s.base : end-s.base]
+}
+
+// discard forgets the source before the input offset end.
+func (s *sourceRecorder) discard(end int64) {
+	s.buf = append(s.buf[:0], s.buf[end-s.base:]...)
+	s.base = end
+}
+
+// emitResult localizes the analyses of a validated node and passes them to the
+// stream callback in category order.
+func emitResult(result *NodeResult, cfg *config) {
+	localizeReasons(result, cfg.messages)
+	categories := make([]string, 0, len(result.Analyses))
+	for category := range result.Analyses {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		cfg.onResult(result.SourcePointer, result.Analyses[category])
+	}
+}
@@ -2,8 +2,11 @@ package validator
 
 import (
 	"context"
+	"encoding/xml"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -20,8 +23,14 @@ var (
 	ErrUnsupportedVersion = errors.New("Unsupported VAST version")
 	// errMissingVMAPVersion indicates the version attribute is missing on <VMAP>.
 	errMissingVMAPVersion = errors.New("Missing VMAP version attribute")
+	// errOverallTimeout is the cause of a run's HTTP context once
+	// HTTPValidationOptions.OverallTimeout elapses.
+	errOverallTimeout = errors.New("overall HTTP validation timeout elapsed")
 )
 
+// versionFormatPattern matches a well-formed major.minor version such as 4.2.
+var versionFormatPattern = regexp.MustCompile(`^\d+\.\d+$`)
+
 // Option configures the validation behavior.
 type Option func(*config)
 
@@ -32,8 +41,61 @@ type config struct {
 	runCustom   bool
 	runHTTP     bool
 	httpOptions HTTPValidationOptions
+	rootElement string
+
+	// catalogLayers are validated in addition to catalog, each under its category.
+	catalogLayers []CatalogLayer
+
+	staticResourceTypes []string
+	checkMacros         bool
+	allowedMacros       []string
+	checkErrorCodeMacro bool
+	requireHTTPS        bool
+	auditAdSystem       bool
+	requireAdServingID  bool
+	strictCDATA         bool
+	maxAds              int
+
+	versionMismatchSummary bool
+
+	// messages overrides reason messages by code; see WithMessages.
+	messages map[string]string
+
+	// probeNodes lists the lower-cased nodes probed by resourceProbeValidator;
+	// nil probes defaultProbeNodes.
+	probeNodes map[string]bool
+
+	// categories restricts the analysis categories reported; nil reports all.
+	categories map[string]bool
+
+	emptyAttributePolicy EmptyAttributePolicy
+
+	verbose  bool
+	snippets bool
+
+	// clockOverride replaces the real clock; see WithClock.
+	clockOverride Clock
+
+	// httpContext is the parent context of every HTTP validator of a run: the
+	// ValidateBatch context, bounded by OverallTimeout when that is set.
+	httpContext context.Context
+
+	// structuralOnly skips summary computation; see WithStructuralOnly.
+	structuralOnly bool
+
+	// onResult receives each node's analyses as it is validated; see
+	// ValidateStream.
+	onResult func(path string, res *NodeAnalysisResult)
+
+	failFast bool
+	// halted is set once fail-fast mode has recorded its first failure.
+	halted bool
 }
 
+// defaultFragmentVersion is the VAST version used to validate fragments whose
+// root element cannot carry a version attribute.
+const defaultFragmentVersion = vast.Version42
+
 func defaultConfig() *config {
 	return &config{
 		catalog:     defaultCatalog,
@@ -42,15 +104,31 @@ func defaultConfig() *config {
 		runCustom:   true,
 		runHTTP:     true,
 		httpOptions: HTTPValidationOptions{Timeout: 2 * time.Second},
+		rootElement: "VAST",
 	}
 }
 
-// WithCatalog allows callers to substitute the catalog used for IAB analysis.
-func WithCatalog(catalog *Catalog) Option {
+// CatalogLayer is an additional catalog, such as a vendor extension catalog,
+// whose results are reported under their own analysis category.
+type CatalogLayer struct {
+	Category string
+	Catalog  *Catalog
+}
+
+// WithCatalog allows callers to substitute the catalog used for IAB analysis. A
+// nil catalog keeps the default. Layers are applied on top: every node a layer
+// knows is validated against it under the layer's category, and such nodes are
+// noted rather than failed when the IAB catalog does not recognize them.
+func WithCatalog(catalog *Catalog, layers ...CatalogLayer) Option {
 	return func(cfg *config) {
 		if catalog != nil {
 			cfg.vastCatalog = catalog
 		}
+		for _, layer := range layers {
+			if layer.Catalog != nil && layer.Category != "" {
+				cfg.catalogLayers = append(cfg.catalogLayers, layer)
+			}
+		}
 	}
 }
 
@@ -80,78 +158,487 @@ func DisableHTTPValidators() Option {
 	}
 }
 
+// WithStructuralOnly runs only the offline IAB checks: the catalog rules and the
+// built-in and extension validators. Custom and HTTP validators are skipped
+// and ValidationResult.Summaries is left nil, so the result holds just the IAB
+// tree. It suits callers such as lint-on-save editor integrations that want
+// no network access and no custom checks. The IAB checks dominate the cost, so
+// the saving is modest: on the ten-ad pod of BenchmarkValidate_StructuralOnly
+// it runs about 10% faster than BenchmarkValidate_Offline, with 2.9k against
+// 3.2k allocs/op.
+func WithStructuralOnly() Option {
+	return func(cfg *config) {
+		cfg.runCustom = false
+		cfg.runHTTP = false
+		cfg.structuralOnly = true
+	}
+}
+
+// WithProbeNodes sets the nodes whose URL is probed over HTTP by the built-in
+// resource probe, such as MediaFile, Mezzanine, StaticResource or
+// IFrameResource. Only MediaFile is probed by default; calling it with no names
+// disables the built-in probe.
+func WithProbeNodes(names ...string) Option {
+	return func(cfg *config) {
+		cfg.probeNodes = map[string]bool{}
+		for _, name := range names {
+			cfg.probeNodes[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// probes reports whether the built-in resource probe runs on nodeName.
+func (cfg *config) probes(nodeName string) bool {
+	if cfg.probeNodes == nil {
+		return isKeyword(nodeName, defaultProbeNodes)
+	}
+	return cfg.probeNodes[strings.ToLower(nodeName)]
+}
+
 // WithHTTPValidationOptions configures the HTTP client/timeout used by HTTP validators.
+// When no Client is given but Proxy, TLSConfig or InsecureSkipVerify is set, a
+// client is built once here and shared by every probe of the run.
 func WithHTTPValidationOptions(opts HTTPValidationOptions) Option {
 	return func(cfg *config) {
+		if opts.Client == nil && opts.customTransport() {
+			opts.Client = opts.client()
+		}
 		cfg.httpOptions = opts
 	}
 }
 
-// Validate parses and validates a VAST XML document.
-func Validate(raw []byte, opts ...Option) (*ValidationResult, error) {
-	if len(raw) == 0 {
-		return nil, errEmptyXML
+// WithClock replaces the clock behind HTTP validator timeouts, retry backoff and
+// recorded timings, so timeout behavior can be tested without real sleeping.
+// The real clock is used by default.
+func WithClock(clock Clock) Option {
+	return func(cfg *config) {
+		cfg.clockOverride = clock
+	}
+}
+
+// WithRootElement validates documents whose root is the named catalog node (for
+// example a bare <Ad> fragment) instead of <VAST>. Fragments have no version
+// attribute, so they are validated against VAST 4.2.
+func WithRootElement(name string) Option {
+	return func(cfg *config) {
+		if trimmed := strings.TrimSpace(name); trimmed != "" {
+			cfg.rootElement = trimmed
+		}
+	}
+}
+
+// WithStaticResourceTypes overrides the MIME types accepted for
+// StaticResource@creativeType. Matching is case-insensitive.
+func WithStaticResourceTypes(types ...string) Option {
+	return func(cfg *config) {
+		cfg.staticResourceTypes = append([]string(nil), types...)
+	}
+}
+
+// WithMacroPolicy enables a check that warns about [MACRO] tokens left in URLs.
+// Macros in allowed (with or without brackets) are expected to be expanded by the
+// player and are ignored; when none are given, the IAB player macros are allowed.
+func WithMacroPolicy(allowed ...string) Option {
+	return func(cfg *config) {
+		cfg.checkMacros = true
+		cfg.allowedMacros = append([]string(nil), allowed...)
+	}
+}
+
+// WithErrorCodeMacroCheck enables a check that warns when an Error URL under
+// VAST, InLine or Wrapper lacks the [ERRORCODE] macro, without which players
+// cannot report which failure occurred.
+func WithErrorCodeMacroCheck() Option {
+	return func(cfg *config) {
+		cfg.checkErrorCodeMacro = true
+	}
+}
+
+// WithRequireHTTPS warns about http:// and protocol-relative (//) URLs in
+// impressions, tracking, clicks, media files, resources and tag URIs, for
+// platforms that only serve ads over https.
+func WithRequireHTTPS() Option {
+	return func(cfg *config) {
+		cfg.requireHTTPS = true
+	}
+}
+
+// WithMaxAds fails a document carrying more than n Ad elements, for platforms
+// that cap standalone responses or pods. A value of zero or less disables the
+// check.
+func WithMaxAds(n int) Option {
+	return func(cfg *config) {
+		cfg.maxAds = n
+	}
+}
+
+// WithAdSystemAudit records the AdSystem version attribute as an informational
+// note for auditing, flagging values that look like the VAST version instead of
+// the ad server's own version.
+func WithAdSystemAudit() Option {
+	return func(cfg *config) {
+		cfg.auditAdSystem = true
+	}
+}
+
+// WithAdServingIDCheck warns when a VAST 4.1+ InLine lacks an AdServingId,
+// which ad servers and verification vendors use to reconcile impressions. The
+// catalog keeps AdServingId optional, so this check is off by default.
+func WithAdServingIDCheck() Option {
+	return func(cfg *config) {
+		cfg.requireAdServingID = true
+	}
+}
+
+// WithStrictCDATA fails nodes the catalog expects in CDATA, such as
+// Impression and MediaFile URLs, whose value was delivered as escaped text
+// (for example &amp;) instead, which some players mishandle.
+func WithStrictCDATA() Option {
+	return func(cfg *config) {
+		cfg.strictCDATA = true
+	}
+}
+
+// WithVersionMismatchSummary adds a single root-level warning, under
+// VersionAnalysisCategory, listing every node and attribute the document uses
+// that its declared version does not support. The per-node failures remain.
+func WithVersionMismatchSummary() Option {
+	return func(cfg *config) {
+		cfg.versionMismatchSummary = true
+	}
+}
+
+// EmptyAttributePolicy controls how attributes present with an empty value are reported.
+type EmptyAttributePolicy string
+
+const (
+	// EmptyAttributeFail reports empty attribute values as failures (the default).
+	EmptyAttributeFail EmptyAttributePolicy = "fail"
+	// EmptyAttributeWarn reports empty attribute values as warnings, for legacy
+	// partners that send width="" instead of omitting the attribute.
+	EmptyAttributeWarn EmptyAttributePolicy = "warn"
+)
+
+// WithEmptyAttributePolicy sets how empty values are reported for attributes
+// that do not allow them. The default is EmptyAttributeFail.
+func WithEmptyAttributePolicy(policy EmptyAttributePolicy) Option {
+	return func(cfg *config) {
+		cfg.emptyAttributePolicy = policy
+	}
+}
+
+// WithVerbose records informational reasons, such as the version check or the
+// number of valid attributes, on analyses that pass. The notes turn the
+// analysis status to StatusInfo and help explain why a node passed.
+func WithVerbose() Option {
+	return func(cfg *config) {
+		cfg.verbose = true
+	}
+}
+
+// WithSnippets records the source XML of each failing node on NodeResult.Snippet.
+// Snippets longer than maxSnippetLength bytes are truncated.
+func WithSnippets() Option {
+	return func(cfg *config) {
+		cfg.snippets = true
+	}
+}
+
+// WithFailFast stops validation at the first IAB failure. The returned result
+// only contains the path from the root to the failing node, which is enough for
+// a quick pass/fail decision on large documents. It has no effect when
+// WithCategories leaves out the IAB category, since the failure would not be
+// reported.
+func WithFailFast() Option {
+	return func(cfg *config) {
+		cfg.failFast = true
+	}
+}
+
+// WithCategories limits validation to the named analysis categories (for example
+// IABAnalysisCategory). Other categories are dropped from node results and
+// summaries, and custom and HTTP validators are skipped entirely when only the
+// IAB category is selected. The IAB checks still run when that category is
+// left out, but WithFailFast no longer stops on them. It composes with
+// DisableCustomValidators and DisableHTTPValidators.
+func WithCategories(categories ...string) Option {
+	return func(cfg *config) {
+		cfg.categories = make(map[string]bool, len(categories))
+		for _, category := range categories {
+			if trimmed := strings.TrimSpace(category); trimmed != "" {
+				cfg.categories[trimmed] = true
+			}
+		}
+	}
+}
+
+// categoryEnabled reports whether results for category should be kept.
+func (cfg *config) categoryEnabled(category string) bool {
+	return cfg.categories == nil || cfg.categories[category]
+}
+
+// runsNonIABCategories reports whether any category other than IAB is selected,
+// which is when custom and HTTP validators can contribute results.
+func (cfg *config) runsNonIABCategories() bool {
+	if cfg.categories == nil {
+		return true
 	}
+	for category := range cfg.categories {
+		if category != IABAnalysisCategory {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneAnalyses drops analyses for categories not selected by WithCategories.
+func pruneAnalyses(result *NodeResult, cfg *config) {
+	if cfg.categories == nil {
+		return
+	}
+	for category := range result.Analyses {
+		if !cfg.categoryEnabled(category) {
+			delete(result.Analyses, category)
+		}
+	}
+	if len(result.Analyses) == 0 {
+		result.Analyses = nil
+	}
+}
 
+// Validate parses and validates a VAST XML document, decompressing it first
+// when it is gzip-compressed. Errors that prevent validation are returned as
+// *ValidateError. Validate never panics: a panic raised while validating, for
+// example by a custom validator, is returned as an InternalError.
+func Validate(raw []byte, opts ...Option) (*ValidationResult, error) {
+	return validateWithConfig(raw, newConfig(opts...))
+}
+
+// newConfig applies the options to the default configuration.
+func newConfig(opts ...Option) *config {
 	cfg := defaultConfig()
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	return cfg
+}
+
+func validateWithConfig(raw []byte, cfg *config) (result *ValidationResult, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = nil
+			err = newValidateError(InternalError, fmt.Errorf("validator: panic during validation: %v", recovered))
+		}
+	}()
+
+	raw, err = decompressDocument(raw)
+	if err != nil {
+		return nil, newValidateError(ParseError, err)
+	}
+	if len(trimDocumentPrefix(raw)) == 0 {
+		return nil, newValidateError(ParseError, errEmptyXML)
+	}
 
 	root, err := buildNodeTree(raw)
+	if err != nil {
+		return nil, newValidateError(ParseError, err)
+	}
+
+	doc, err := resolveRoot(root, cfg)
 	if err != nil {
 		return nil, err
 	}
+	defer startOverallTimeout(cfg)()
+
+	rootPointer := buildSourcePointer("", doc.name, 1)
+	rootResult := validateNodeRecursive(root, doc.version, cfg, doc.spec, nil, false, "", false, false, rootPointer)
+	finishRoot(rootResult, doc, cfg)
+	localizeReasons(rootResult, cfg.messages)
+
+	result = &ValidationResult{Version: doc.version, Root: rootResult}
+	if !cfg.structuralOnly {
+		result.Summaries = summarizeCategories(rootResult)
+	}
+	if cfg.httpOptions.RecordTimings {
+		result.HTTPDurationMs = totalDurationMillis(rootResult)
+	}
+	return result, nil
+}
+
+// documentRoot describes the root element a document is validated under.
+type documentRoot struct {
+	name    string
+	spec    *NodeSpec
+	version vast.Version
+	isVMAP  bool
+}
 
+// resolveRoot selects the catalog for the document's root element, recording
+// it on cfg, and reads the document version.
+func resolveRoot(root *genericNode, cfg *config) (*documentRoot, error) {
 	rootName := root.localName()
 	var (
 		catalogForDoc *Catalog
-		rootNodeName  string
-		isVMAP        bool
+		isFragment    bool
 	)
+	doc := &documentRoot{}
 	switch {
+	case !strings.EqualFold(cfg.rootElement, "VAST"):
+		if !strings.EqualFold(rootName, cfg.rootElement) {
+			return nil, newValidateError(RootError, ErrInvalidRoot)
+		}
+		doc.name = cfg.rootElement
+		catalogForDoc = cfg.vastCatalog
+		isFragment = true
 	case strings.EqualFold(rootName, "VAST"):
-		rootNodeName = "VAST"
+		doc.name = "VAST"
 		catalogForDoc = cfg.vastCatalog
 	case strings.EqualFold(rootName, "VMAP"):
-		rootNodeName = "VMAP"
+		doc.name = "VMAP"
 		catalogForDoc = cfg.vmapCatalog
-		isVMAP = true
+		doc.isVMAP = true
 	default:
-		return nil, ErrInvalidRoot
+		return nil, newValidateError(RootError, ErrInvalidRoot)
 	}
 	if catalogForDoc == nil {
-		return nil, fmt.Errorf("validator: no catalog configured for %s root", rootNodeName)
+		return nil, fmt.Errorf("validator: no catalog configured for %s root", doc.name)
 	}
 	cfg.catalog = catalogForDoc
 
-	versionValue, ok := root.attrValue("version")
-	if !ok || strings.TrimSpace(versionValue) == "" {
-		if rootNodeName == "VAST" {
-			return nil, ErrMissingVersion
+	doc.version = defaultFragmentVersion
+	if !isFragment {
+		versionValue, ok := root.attrValue("version")
+		if !ok || strings.TrimSpace(versionValue) == "" {
+			if doc.name == "VAST" {
+				return nil, newValidateError(VersionError, ErrMissingVersion)
+			}
+			return nil, newValidateError(VersionError, errMissingVMAPVersion)
 		}
-		return nil, errMissingVMAPVersion
+		doc.version = vast.Version(strings.TrimSpace(versionValue))
+	}
+
+	spec, ok := catalogForDoc.node(doc.name)
+	if !ok {
+		return nil, fmt.Errorf("validator: catalog missing %s spec", doc.name)
 	}
-	version := vast.Version(strings.TrimSpace(versionValue))
+	doc.spec = spec
+	return doc, nil
+}
 
-	rootSpec, hasRootSpec := catalogForDoc.node(rootNodeName)
-	if !hasRootSpec {
-		return nil, fmt.Errorf("validator: catalog missing %s spec", rootNodeName)
+// startOverallTimeout bounds the HTTP validators of a run when OverallTimeout
+// is set, on top of any context the run already has. The returned function
+// releases the timer.
+func startOverallTimeout(cfg *config) context.CancelFunc {
+	if !cfg.runHTTP || cfg.httpOptions.OverallTimeout <= 0 {
+		return func() {}
 	}
-	rootVersionSupported := rootSpec.supports(version)
+	parent := context.Background()
+	if cfg.httpContext != nil {
+		parent = cfg.httpContext
+	}
+	ctx, cancel := withClockTimeout(parent, cfg.clock(), cfg.httpOptions.OverallTimeout, errOverallTimeout)
+	cfg.httpContext = ctx
+	return cancel
+}
 
-	rootPointer := buildSourcePointer("", rootNodeName, 1)
-	rootResult := validateNodeRecursive(root, version, cfg, rootSpec, nil, false, "", false, false, rootPointer)
-	if !rootVersionSupported {
+// finishRoot records the document-level findings on the root result.
+func finishRoot(rootResult *NodeResult, doc *documentRoot, cfg *config) {
+	if !doc.spec.supports(doc.version) {
 		iab := rootResult.addAnalysis(IABAnalysisCategory)
-		markFailure(iab, fmt.Sprintf("Unsupported %s version: %s", rootNodeName, version))
+		if versionFormatPattern.MatchString(string(doc.version)) {
+			markFailure(iab, ReasonUnsupportedVersion, "Unsupported {node} version: {version}", "node", doc.name, "version", doc.version)
+		} else {
+			markFailure(iab, ReasonInvalidVersionFormat, `Malformed {node} version "{version}": expected major.minor, e.g. 4.2`, "node", doc.name, "version", doc.version)
+		}
 	}
-	if isVMAP {
+	if doc.isVMAP {
 		iab := rootResult.addAnalysis(IABAnalysisCategory)
-		markInformational(iab, "VMAP validation is informational only.")
+		markInformational(iab, ReasonInformationalOnly, "VMAP validation is informational only.")
+	}
+	if cfg.versionMismatchSummary {
+		addVersionMismatchSummary(rootResult, doc.version)
+	}
+	pruneAnalyses(rootResult, cfg)
+}
+
+// addVersionMismatchSummary collects the nodes and attributes failed as
+// unsupported in version and records them on the root in one warning.
+func addVersionMismatchSummary(root *NodeResult, version vast.Version) {
+	var features []string
+	seen := map[string]bool{}
+	add := func(feature string, introducedAt *float64) {
+		if introducedAt != nil {
+			feature = fmt.Sprintf("%s (%s)", feature, strconv.FormatFloat(*introducedAt, 'f', 1, 64))
+		}
+		if !seen[feature] {
+			seen[feature] = true
+			features = append(features, feature)
+		}
+	}
+	var walk func(node *NodeResult)
+	walk = func(node *NodeResult) {
+		if iab := node.Analyses[IABAnalysisCategory]; iab != nil {
+			for _, reason := range iab.Reasons {
+				if reason.Code == ReasonUnsupportedNode {
+					add(node.Node, node.IntroducedAt)
+					break
+				}
+			}
+			for _, attr := range iab.Attributes {
+				for _, reason := range attr.Reasons {
+					if reason.Code == ReasonUnsupportedAttribute {
+						add(node.Node+"@"+attr.Name, attr.IntroducedAt)
+						break
+					}
+				}
+			}
+		}
+		for _, child := range node.Children {
+			walk(child)
+		}
 	}
+	walk(root)
+	if len(features) == 0 {
+		return
+	}
+	analysis := root.addAnalysis(VersionAnalysisCategory)
+	markWarning(analysis, ReasonVersionMismatch, "version {version} document uses {count} unsupported feature(s): {features}", "version", version, "count", len(features), "features", strings.Join(features, ", "))
+}
+
+// extensionState returns the extension type, backport subtree and extension
+// container state that node passes down to its children.
+func extensionState(node *genericNode, spec *NodeSpec, extensionType string, inBackportSubtree, inExtensionContainer bool) (string, bool, bool) {
+	if spec != nil && isExtensionContainerSpec(spec) {
+		extensionType = ""
+		if value, ok := node.attrValue("type"); ok {
+			extensionType = strings.TrimSpace(value)
+		}
+		inExtensionContainer = true
+	}
+	backportEligible := spec != nil && spec.SupportsExtensions && extensionType != "" && strings.EqualFold(extensionType, spec.Name)
+	return extensionType, inBackportSubtree || backportEligible, inExtensionContainer
+}
 
-	return &ValidationResult{Version: version, Root: rootResult, Summaries: summarizeCategories(rootResult)}, nil
+// childSpecFor resolves the catalog spec of a child of spec, following the
+// parent's NodeOverride when the child is validated under another spec.
+func childSpecFor(catalog *Catalog, spec *NodeSpec, childName string) *NodeSpec {
+	lookupName := childName
+	if spec != nil {
+		if parentChild, ok := spec.child(childName); ok {
+			if parentChild.NodeOverride != "" {
+				lookupName = parentChild.NodeOverride
+			}
+		} else if parentChild, _, ok := spec.childCaseInsensitive(childName); ok {
+			if parentChild.NodeOverride != "" {
+				lookupName = parentChild.NodeOverride
+			}
+		}
+	}
+	childSpec, _ := catalog.node(lookupName)
+	if childSpec == nil && lookupName != childName {
+		childSpec, _ = catalog.node(childName)
+	}
+	return childSpec
 }
 
 func validateNodeRecursive(node *genericNode, version vast.Version, cfg *config, spec *NodeSpec, parentSpec *NodeSpec, parentAllowsUnknown bool, extensionType string, inBackportSubtree bool, inExtensionContainer bool, sourcePointer string) *NodeResult {
@@ -168,46 +655,40 @@ func validateNodeRecursive(node *genericNode, version vast.Version, cfg *config,
 			nodeCaseMismatch = canonicalName
 		}
 	}
-	currentExtensionType := extensionType
-	currentInExtensionContainer := inExtensionContainer
-	if spec != nil && isExtensionContainerSpec(spec) {
-		if value, ok := node.attrValue("type"); ok {
-			currentExtensionType = strings.TrimSpace(value)
-		} else {
-			currentExtensionType = ""
-		}
-		currentInExtensionContainer = true
-	}
-	backportEligible := spec != nil && spec.SupportsExtensions && currentExtensionType != "" && strings.EqualFold(currentExtensionType, spec.Name)
-	currentBackportSubtree := inBackportSubtree || backportEligible
+	currentExtensionType, currentBackportSubtree, currentInExtensionContainer := extensionState(node, spec, extensionType, inBackportSubtree, inExtensionContainer)
 
 	if spec != nil {
 		result.VersionSupport = spec.Versions
 		result.IntroducedAt = introducedAtFromVersions(spec.Versions)
 	}
 
+	layers := cfg.layersFor(result.Node)
 	iabAnalysis := result.addAnalysis(IABAnalysisCategory)
 	if spec == nil {
 		if !parentAllowsUnknown {
-			markFailure(iabAnalysis, fmt.Sprintf("node %s is not recognized in the IAB catalog. Check the spelling and or casing.", result.Node))
+			if len(layers) > 0 {
+				markInformational(iabAnalysis, ReasonVendorNode, "node {node} is not in the IAB catalog; it is validated under {categories}", "node", result.Node, "categories", layerCategories(layers))
+			} else {
+				markFailure(iabAnalysis, ReasonUnknownNode, "node {node} is not recognized in the IAB catalog. Check the spelling and or casing.", "node", result.Node)
+			}
 		}
 	} else {
 		if nodeCaseMismatch != "" && nodeCaseMismatch != result.Node {
-			markFailure(iabAnalysis, fmt.Sprintf("node %s casing is invalid; use %s", result.Node, nodeCaseMismatch))
+			markFailure(iabAnalysis, ReasonInvalidCasing, "node {node} casing is invalid; use {expected}", "node", result.Node, "expected", nodeCaseMismatch)
 		}
 		if !spec.supports(version) && !currentBackportSubtree {
 			reportedBackportRequirement := false
 			if spec.SupportsExtensions && currentInExtensionContainer {
 				if currentExtensionType == "" {
-					markFailure(iabAnalysis, fmt.Sprintf("Extension attribute type must be %s. Add the attribute type='%s' to the extension node.", spec.Name, spec.Name))
+					markFailure(iabAnalysis, ReasonExtensionTypeMissing, "Extension attribute type must be {expected}. Add the attribute type='{expected}' to the extension node.", "expected", spec.Name)
 					reportedBackportRequirement = true
 				} else if !strings.EqualFold(currentExtensionType, spec.Name) {
-					markFailure(iabAnalysis, fmt.Sprintf("Extension attribute type %s does not match %s", currentExtensionType, spec.Name))
+					markFailure(iabAnalysis, ReasonExtensionTypeMismatch, "Extension attribute type {type} does not match {expected}", "type", currentExtensionType, "expected", spec.Name)
 					reportedBackportRequirement = true
 				}
 			}
 			if !reportedBackportRequirement {
-				markFailure(iabAnalysis, fmt.Sprintf("node %s is not supported in version %s", result.Node, version))
+				markFailure(iabAnalysis, ReasonUnsupportedNode, "node {node} is not supported in version {version}", "node", result.Node, "version", version)
 			}
 		}
 		if parentSpec != nil && !parentAllowsUnknown {
@@ -221,36 +702,70 @@ func validateNodeRecursive(node *genericNode, version vast.Version, cfg *config,
 				}
 			}
 			if !ok {
-				markFailure(iabAnalysis, fmt.Sprintf("node %s is not a valid child of %s", result.Node, parentSpec.Name))
+				message, args := "node {node} is not a valid child of {parent}", []any{"node", result.Node, "parent", parentSpec.Name}
+				if parents := cfg.catalog.parentsOf(spec.Name); len(parents) > 0 {
+					message += "; it belongs under {parents}"
+					args = append(args, "parents", strings.Join(parents, " or "))
+				}
+				markFailure(iabAnalysis, ReasonInvalidChild, message, args...)
 			} else {
 				if childCaseMismatch != "" && childCaseMismatch != result.Node {
-					markFailure(iabAnalysis, fmt.Sprintf("child node %s casing is invalid for parent %s; use %s", result.Node, parentSpec.Name, childCaseMismatch))
+					markFailure(iabAnalysis, ReasonInvalidCasing, "child node {node} casing is invalid for parent {parent}; use {expected}", "node", result.Node, "parent", parentSpec.Name, "expected", childCaseMismatch)
 				}
 				if !childSpec.supports(version) {
-					markFailure(iabAnalysis, fmt.Sprintf("node %s is not allowed for parent %s in version %s", result.Node, parentSpec.Name, version))
+					markFailure(iabAnalysis, ReasonUnsupportedNode, "node {node} is not allowed for parent {parent} in version {version}", "node", result.Node, "parent", parentSpec.Name, "version", version)
 				}
 			}
 		}
 	}
 
-	if !parentAllowsUnknown || currentBackportSubtree {
-		validateAttributes(node, version, spec, iabAnalysis, currentBackportSubtree)
+	validateDuplicateAttributes(node, iabAnalysis)
+
+	// Attributes of vendor-only nodes are checked by their catalog layer.
+	vendorOnly := spec == nil && len(layers) > 0
+	if (!parentAllowsUnknown || currentBackportSubtree) && !vendorOnly {
+		validateAttributes(node, version, spec, iabAnalysis, currentBackportSubtree, cfg.emptyAttributePolicy)
 	}
 
 	if spec != nil && spec.RequiresValue && strings.TrimSpace(node.Content) == "" {
-		markFailure(iabAnalysis, fmt.Sprintf("node %s requires a non-empty text value", spec.Name))
+		markFailure(iabAnalysis, ReasonMissingValue, "node {node} requires a non-empty text value", "node", spec.Name)
+	}
+
+	if cfg.strictCDATA && spec != nil && spec.NeedsCDATA && node.EscapedText {
+		markFailure(iabAnalysis, ReasonEscapedText, "node {node} value uses escaped entities; wrap it in CDATA", "node", spec.Name)
 	}
 
+	applyCatalogLayers(result, node, version, cfg, layers)
+
 	if isExtensionContainerSpec(spec) {
 		applyExtensionValidators(result, node, version)
 	}
+	applyBuiltInValidators(result, node, version, cfg)
 
-	if cfg.runCustom {
+	if spec != nil && !parentAllowsUnknown {
+		validateChildren(node, version, spec, iabAnalysis)
+	}
+
+	if cfg.failFast && cfg.categoryEnabled(IABAnalysisCategory) && iabAnalysis.Status == StatusFail {
+		cfg.halted = true
+		pruneAnalyses(result, cfg)
+		attachSnippet(result, node, cfg)
+		return result
+	}
+
+	if cfg.runCustom && cfg.runsNonIABCategories() {
 		applyCustomValidators(result, node, version)
+		applyAttributeValidators(result, node, version)
 	}
-	if cfg.runHTTP {
+	if cfg.runHTTP && cfg.runsNonIABCategories() {
 		applyHTTPValidators(result, node, version, cfg)
 	}
+	pruneAnalyses(result, cfg)
+
+	if cfg.verbose {
+		addVerboseNotes(iabAnalysis, version, spec)
+	}
+	attachSnippet(result, node, cfg)
 
 	childAllowsUnknown := parentAllowsUnknown
 	if spec != nil && spec.AllowUnknownChildren {
@@ -261,30 +776,100 @@ func validateNodeRecursive(node *genericNode, version vast.Version, cfg *config,
 	for _, child := range node.Children {
 		childName := child.localName()
 		childOccurrences[childName]++
-		childLookupName := childName
-		if spec != nil {
-			if parentChild, ok := spec.child(childName); ok {
-				if parentChild.NodeOverride != "" {
-					childLookupName = parentChild.NodeOverride
-				}
-			} else if parentChild, _, ok := spec.childCaseInsensitive(childName); ok {
-				if parentChild.NodeOverride != "" {
-					childLookupName = parentChild.NodeOverride
-				}
-			}
-		}
-		childSpec, _ := cfg.catalog.node(childLookupName)
-		if childSpec == nil && childLookupName != childName {
-			childSpec, _ = cfg.catalog.node(childName)
+		if child.released {
+			// ValidateStream already validated and reported this subtree.
+			continue
 		}
+		childSpec := childSpecFor(cfg.catalog, spec, childName)
 		childPointer := buildSourcePointer(sourcePointer, childName, childOccurrences[childName])
 		childResult := validateNodeRecursive(child, version, cfg, childSpec, spec, childAllowsUnknown, currentExtensionType, currentBackportSubtree, currentInExtensionContainer, childPointer)
+		if cfg.onResult != nil {
+			// Streamed results are reported and released instead of kept in the tree.
+			emitResult(childResult, cfg)
+			child.Children = nil
+			if cfg.halted {
+				break
+			}
+			continue
+		}
+		if cfg.halted {
+			// Drop the passing siblings so only the path to the failure remains.
+			result.Children = []*NodeResult{childResult}
+			break
+		}
 		result.Children = append(result.Children, childResult)
 	}
 
 	return result
 }
 
+// layeredSpec is a node spec found in one of the configured catalog layers.
+type layeredSpec struct {
+	category string
+	spec     *NodeSpec
+}
+
+// layersFor returns the catalog layers that define the named node.
+func (cfg *config) layersFor(name string) []layeredSpec {
+	var found []layeredSpec
+	for _, layer := range cfg.catalogLayers {
+		if spec, ok := layer.Catalog.node(name); ok {
+			found = append(found, layeredSpec{category: layer.Category, spec: spec})
+		}
+	}
+	return found
+}
+
+func layerCategories(layers []layeredSpec) string {
+	categories := make([]string, len(layers))
+	for i, layer := range layers {
+		categories[i] = layer.category
+	}
+	return strings.Join(categories, ", ")
+}
+
+// applyCatalogLayers validates the node against each layer that defines it,
+// reporting version support, attributes, content and repeated children under
+// the layer's category.
+func applyCatalogLayers(result *NodeResult, node *genericNode, version vast.Version, cfg *config, layers []layeredSpec) {
+	for _, layer := range layers {
+		analysis := result.addAnalysis(layer.category)
+		if !layer.spec.supports(version) {
+			markFailure(analysis, ReasonUnsupportedNode, "node {node} is not supported in version {version}", "node", result.Node, "version", version)
+		}
+		validateAttributes(node, version, layer.spec, analysis, false, cfg.emptyAttributePolicy)
+		if layer.spec.RequiresValue && strings.TrimSpace(node.Content) == "" {
+			markFailure(analysis, ReasonMissingValue, "node {node} requires a non-empty text value", "node", layer.spec.Name)
+		}
+		validateChildren(node, version, layer.spec, analysis)
+	}
+}
+
+// maxSnippetLength bounds the size of a NodeResult.Snippet.
+const maxSnippetLength = 4096
+
+// attachSnippet copies the node's source XML onto a failing result.
+func attachSnippet(result *NodeResult, node *genericNode, cfg *config) {
+	if !cfg.snippets || len(node.Raw) == 0 {
+		return
+	}
+	failing := false
+	for _, analysis := range result.Analyses {
+		if analysis.Status == StatusFail {
+			failing = true
+			break
+		}
+	}
+	if !failing {
+		return
+	}
+	if len(node.Raw) > maxSnippetLength {
+		result.Snippet = string(node.Raw[:maxSnippetLength]) + "..."
+		return
+	}
+	result.Snippet = string(node.Raw)
+}
+
 // applyExtensionValidators executes registered extension validators that match the given node and merges their results into the provided node result.
 func buildSourcePointer(parentPointer, nodeName string, occurrence int) string {
 	if nodeName == "" {
@@ -299,31 +884,95 @@ func buildSourcePointer(parentPointer, nodeName string, occurrence int) string {
 	return fmt.Sprintf("%s/%s[%d]", parentPointer, nodeName, occurrence)
 }
 
-func validateAttributes(node *genericNode, version vast.Version, spec *NodeSpec, analysis *NodeAnalysisResult, allowBackport bool) {
+// validateChildren fails the node for each child its spec allows at most once
+// that the document repeats.
+func validateChildren(node *genericNode, version vast.Version, spec *NodeSpec, analysis *NodeAnalysisResult) {
+	if len(spec.Children) == 0 {
+		return
+	}
+	// Count children by their catalog key so each child costs one map lookup.
+	counts := make(map[string]int, len(spec.Children))
+	for _, child := range node.Children {
+		name := child.localName()
+		if _, ok := spec.Children[name]; !ok {
+			if _, key, ok := spec.childCaseInsensitive(name); ok {
+				name = key
+			}
+		}
+		counts[name]++
+	}
+	var repeated []string
+	for key, childSpec := range spec.Children {
+		if childSpec.supports(version) && !childSpec.Multiple && counts[key] > 1 {
+			repeated = append(repeated, key)
+		}
+	}
+	sort.Strings(repeated)
+	for _, key := range repeated {
+		markFailure(analysis, ReasonRepeatedChild, "node {node} allows at most one {child}, found {count}", "node", spec.Name, "child", spec.Children[key].Name, "count", counts[key])
+	}
+}
+
+// addVerboseNotes records why a node passed its IAB checks. Notes are only added
+// to analyses without warnings or failures so they never bury real problems.
+func addVerboseNotes(analysis *NodeAnalysisResult, version vast.Version, spec *NodeSpec) {
+	if spec == nil || statusSeverity(analysis.Status) > statusSeverity(StatusInfo) {
+		return
+	}
+	if spec.supports(version) {
+		markInformational(analysis, ReasonVerbose, "node {node} supported in version {version}", "node", spec.Name, "version", version)
+	}
+	if attributes := len(analysis.Attributes); attributes > 0 {
+		markInformational(analysis, ReasonVerbose, "{count} attribute(s) valid", "count", attributes)
+	}
+}
+
+// validateDuplicateAttributes fails an element that repeats an attribute, which
+// encoding/xml accepts but makes the document malformed.
+func validateDuplicateAttributes(node *genericNode, analysis *NodeAnalysisResult) {
+	seen := map[xml.Name]int{}
+	for _, attr := range node.Attrs {
+		seen[attr.Name]++
+		if seen[attr.Name] == 2 {
+			markFailure(analysis, ReasonDuplicateAttribute, "attribute {attribute} is repeated on {node}", "attribute", qualifiedAttrName(attr.Name), "node", node.localName())
+		}
+	}
+}
+
+func validateAttributes(node *genericNode, version vast.Version, spec *NodeSpec, analysis *NodeAnalysisResult, allowBackport bool, emptyPolicy EmptyAttributePolicy) {
 	seen := map[string]bool{}
 
 	for _, attr := range node.Attrs {
-		attrName := attr.Name.Local
-		if attr.Name.Space != "" || strings.EqualFold(attrName, "xmlns") {
-			// Skip namespace declarations or namespace-scoped attributes; they are not part of VAST validation.
+		attrName := qualifiedAttrName(attr.Name)
+		if strings.EqualFold(attrName, "xmlns") || attr.Name.Space == "xmlns" {
+			continue
+		}
+		namespacedSpec, hasNamespacedSpec := spec.attribute(attrName)
+		if attr.Name.Space != "" && (!hasNamespacedSpec || !namespaceMatches(attr.Name.Space, namespacedSpec.Namespace)) {
+			// Namespace-scoped attributes are not part of VAST validation unless the
+			// catalog lists them under their prefixed name and namespace (e.g. xml:lang).
 			continue
 		}
-		attributeResult := AttributeResult{Name: attrName, Status: StatusPass}
+		attributeResult := AttributeResult{Name: attrName, Namespace: attr.Name.Space, Status: StatusPass}
 		resolvedName := attrName
 
 		if spec == nil {
 			seen[resolvedName] = true
 			attributeResult.Status = StatusFail
-			msg := "node is not recognized; attribute cannot be validated"
-			attributeResult.addReason(msg)
+			reason := newReason(ReasonUnknownAttribute, "node is not recognized; attribute {attribute} cannot be validated", "attribute", attrName)
+			attributeResult.addReason(reason)
 			analysis.addAttribute(attributeResult)
-			markFailure(analysis, msg)
+			markStatus(analysis, StatusFail, reason)
 			continue
 		}
 
-		attrSpec, ok := spec.attribute(attrName)
+		attrSpec, ok := namespacedSpec, hasNamespacedSpec
+		if ok && !namespaceMatches(attr.Name.Space, attrSpec.Namespace) {
+			// A plain attribute never satisfies a namespaced spec of the same name.
+			attrSpec, ok = nil, false
+		}
 		caseMismatchName := ""
-		if !ok {
+		if !ok && attr.Name.Space == "" {
 			if matchedAttr, canonicalName, matchOk := spec.attributeCaseInsensitive(attrName); matchOk {
 				attrSpec = matchedAttr
 				caseMismatchName = canonicalName
@@ -336,16 +985,15 @@ func validateAttributes(node *genericNode, version vast.Version, spec *NodeSpec,
 		if !ok {
 			if spec.AllowUnknownAttributes {
 				attributeResult.Status = StatusInfo
-				msg := fmt.Sprintf("attribute %s is not defined in the catalog for %s; treating as custom", attrName, spec.Name)
-				attributeResult.addReason(msg)
+				attributeResult.addReason(newReason(ReasonUnknownAttribute, "attribute {attribute} is not defined in the catalog for {node}; treating as custom", "attribute", attrName, "node", spec.Name))
 				analysis.addAttribute(attributeResult)
 				continue
 			}
 			attributeResult.Status = StatusFail
-			msg := fmt.Sprintf("attribute %s is not allowed on %s for version %s", attrName, spec.Name, version)
-			attributeResult.addReason(msg)
+			reason := newReason(ReasonUnknownAttribute, "attribute {attribute} is not allowed on {node} for version {version}", "attribute", attrName, "node", spec.Name, "version", version)
+			attributeResult.addReason(reason)
 			analysis.addAttribute(attributeResult)
-			markFailure(analysis, msg)
+			markStatus(analysis, StatusFail, reason)
 			continue
 		}
 		attributeResult.VersionSupport = attrSpec.Versions
@@ -354,32 +1002,46 @@ func validateAttributes(node *genericNode, version vast.Version, spec *NodeSpec,
 
 		if caseMismatchName != "" && caseMismatchName != attrName {
 			attributeResult.Status = StatusFail
-			msg := fmt.Sprintf("attribute %s casing is invalid; use %s", attrName, caseMismatchName)
-			attributeResult.addReason(msg)
-			markFailure(analysis, msg)
+			reason := newReason(ReasonInvalidCasing, "attribute {attribute} casing is invalid; use {expected}", "attribute", attrName, "expected", caseMismatchName)
+			attributeResult.addReason(reason)
+			markStatus(analysis, StatusFail, reason)
 		}
 
 		if !attrSpec.supports(version) && !allowBackport {
 			attributeResult.Status = StatusFail
-			msg := fmt.Sprintf("attribute %s is not supported in version %s", attrName, version)
-			attributeResult.addReason(msg)
-			markFailure(analysis, msg)
+			reason := newReason(ReasonUnsupportedAttribute, "attribute {attribute} is not supported in version {version}", "attribute", attrName, "version", version)
+			attributeResult.addReason(reason)
+			markStatus(analysis, StatusFail, reason)
 		}
 
 		value := strings.TrimSpace(attr.Value)
 		if value == "" && !attrSpec.AllowEmpty {
-			attributeResult.Status = StatusFail
-			msg := fmt.Sprintf("attribute %s cannot be empty", attrName)
-			attributeResult.addReason(msg)
-			markFailure(analysis, msg)
+			reason := newReason(ReasonEmptyAttribute, "attribute {attribute} cannot be empty", "attribute", attrName)
+			attributeResult.addReason(reason)
+			if emptyPolicy == EmptyAttributeWarn {
+				if moreSevereStatus(attributeResult.Status, StatusWarning) {
+					attributeResult.Status = StatusWarning
+				}
+				markStatus(analysis, StatusWarning, reason)
+			} else {
+				attributeResult.Status = StatusFail
+				markStatus(analysis, StatusFail, reason)
+			}
 		} else {
 			attributeResult.Value = value
 			if errs := validateAttributeValue(resolvedName, value, attrSpec); len(errs) > 0 {
 				attributeResult.Status = StatusFail
-				for _, errMsg := range errs {
-					attributeResult.addReason(errMsg)
+				for _, reason := range errs {
+					attributeResult.addReason(reason)
+				}
+				markStatus(analysis, StatusFail, errs...)
+			} else if attrSpec.Value != nil && len(attrSpec.Value.KnownValues) > 0 && !isKeyword(value, attrSpec.Value.KnownValues) {
+				reason := newReason(ReasonUnknownAttributeValue, "attribute {attribute} value {value} is not a known value ({expected})", "attribute", resolvedName, "value", value, "expected", strings.Join(attrSpec.Value.KnownValues, ", "))
+				attributeResult.addReason(reason)
+				if moreSevereStatus(attributeResult.Status, StatusWarning) {
+					attributeResult.Status = StatusWarning
 				}
-				markFailure(analysis, errs...)
+				markStatus(analysis, StatusWarning, reason)
 			}
 		}
 
@@ -397,16 +1059,16 @@ func validateAttributes(node *genericNode, version vast.Version, spec *NodeSpec,
 		if seen[attrSpec.Name] {
 			continue
 		}
-		msg := fmt.Sprintf("missing required attribute %s", attrSpec.Name)
+		reason := newReason(ReasonMissingRequiredAttribute, "missing required attribute {attribute}", "attribute", attrSpec.Name)
 		analysis.addAttribute(AttributeResult{
 			Name:           attrSpec.Name,
 			IntroducedAt:   introducedAtFromVersions(attrSpec.Versions),
 			AllowedValues:  copyAllowedValues(attrSpec),
 			VersionSupport: attrSpec.Versions,
 			Status:         StatusFail,
-			Reasons:        []string{msg},
+			Reasons:        []Reason{reason},
 		})
-		markFailure(analysis, msg)
+		markStatus(analysis, StatusFail, reason)
 	}
 }
 
@@ -454,7 +1116,21 @@ func introducedAtFromVersions(versions []vast.Version) *float64 {
 	return &min
 }
 
+// versionNumbers holds the numeric value of the VAST versions the catalogs use,
+// sparing introducedAtFromVersions a float parse for every node and attribute.
+var versionNumbers = map[vast.Version]float64{
+	vast.Version20: 2.0,
+	vast.Version30: 3.0,
+	vast.Version40: 4.0,
+	vast.Version41: 4.1,
+	vast.Version42: 4.2,
+	vast.Version43: 4.3,
+}
+
 func vastVersionToFloat(version vast.Version) (float64, bool) {
+	if value, ok := versionNumbers[version]; ok {
+		return value, true
+	}
 	trimmed := strings.TrimSpace(string(version))
 	if trimmed == "" {
 		return 0, false
@@ -479,27 +1155,66 @@ func applyCustomValidators(nodeResult *NodeResult, node *genericNode, version va
 	}
 }
 
+// applyAttributeValidators runs the attribute validators registered for each
+// attribute present on the node.
+func applyAttributeValidators(nodeResult *NodeResult, node *genericNode, version vast.Version) {
+	for _, attr := range node.Attrs {
+		for _, validator := range getAttributeValidators(nodeResult.Node, qualifiedAttrName(attr.Name)) {
+			analysis := validator(attr.Value, NodeContext{Node: node, Version: version})
+			if analysis == nil {
+				continue
+			}
+			if analysis.Category == "" {
+				analysis.Category = CustomAnalysisCategory
+			}
+			mergeAnalysis(nodeResult, analysis)
+		}
+	}
+}
+
 func applyHTTPValidators(nodeResult *NodeResult, node *genericNode, version vast.Version, cfg *config) {
 	validators := getHTTPValidators(nodeResult.Node)
+	if cfg.probes(nodeResult.Node) {
+		validators = append([]HTTPValidatorFunc{resourceProbeValidator}, validators...)
+	}
 	if len(validators) == 0 {
 		return
 	}
 	ctx := context.Background()
-	if cfg.httpOptions.Timeout > 0 {
+	if cfg.httpContext != nil {
+		ctx = cfg.httpContext
+	}
+	if ctx.Err() != nil {
+		mergeAnalysis(nodeResult, skippedHTTPAnalysis(ctx))
+		return
+	}
+	clock := cfg.clock()
+	if timeout := cfg.httpOptions.timeoutFor(nodeResult.Node); timeout > 0 {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, cfg.httpOptions.Timeout)
+		ctx, cancel = withClockTimeout(ctx, clock, timeout, context.DeadlineExceeded)
 		defer cancel()
 	}
+	ctx = contextWithHTTPOptions(ctx, cfg.httpOptions)
+	ctx = contextWithClock(ctx, clock)
 	client := cfg.httpOptions.client()
 	for _, validator := range validators {
+		started := clock.Now()
 		analysis, err := validator(ctx, NodeContext{Node: node, Version: version}, client)
+		elapsed := clock.Now().Sub(started)
 		if err != nil {
 			analysis = &NodeAnalysisResult{Category: CustomAnalysisCategory}
-			markFailure(analysis, err.Error())
+			markFailure(analysis, ReasonValidatorError, "{error}", "error", err)
+		}
+		if analysis != nil && analysis.Status == StatusFail && cfg.httpContext != nil && cfg.httpContext.Err() != nil {
+			// The probe was cut short by the run, not by the URL.
+			analysis = skippedHTTPAnalysis(cfg.httpContext)
 		}
 		if analysis == nil {
 			continue
 		}
+		if cfg.httpOptions.RecordTimings {
+			analysis.DurationMs = durationMillis(elapsed)
+		}
 		if analysis.Category == "" {
 			analysis.Category = CustomAnalysisCategory
 		}
@@ -507,6 +1222,37 @@ func applyHTTPValidators(nodeResult *NodeResult, node *genericNode, version vast
 	}
 }
 
+// skippedHTTPAnalysis records an HTTP validator skipped or cut short because
+// ctx ended. Only HTTPValidationOptions.OverallTimeout is reported as
+// ReasonOverallTimeout; the ValidateBatch context being cancelled or reaching
+// its own deadline is reported as ReasonCanceled.
+func skippedHTTPAnalysis(ctx context.Context) *NodeAnalysisResult {
+	reason := newReason(ReasonCanceled, "skipped because validation was cancelled")
+	if errors.Is(context.Cause(ctx), errOverallTimeout) {
+		reason = newReason(ReasonOverallTimeout, "skipped due to overall timeout")
+	}
+	return &NodeAnalysisResult{Category: CustomAnalysisCategory, Status: StatusInfo, Reasons: []Reason{reason}}
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// totalDurationMillis sums the recorded durations across every analysis in the tree.
+func totalDurationMillis(root *NodeResult) float64 {
+	if root == nil {
+		return 0
+	}
+	var total float64
+	for _, analysis := range root.Analyses {
+		total += analysis.DurationMs
+	}
+	for _, child := range root.Children {
+		total += totalDurationMillis(child)
+	}
+	return total
+}
+
 func mergeAnalysis(nodeResult *NodeResult, analysis *NodeAnalysisResult) {
 	if nodeResult.Analyses == nil {
 		nodeResult.Analyses = make(map[string]*NodeAnalysisResult)
@@ -517,26 +1263,30 @@ func mergeAnalysis(nodeResult *NodeResult, analysis *NodeAnalysisResult) {
 		return
 	}
 	existing.Attributes = append(existing.Attributes, analysis.Attributes...)
+	existing.DurationMs += analysis.DurationMs
 	markStatus(existing, analysis.Status, analysis.Reasons...)
 }
 
-func markFailure(analysis *NodeAnalysisResult, reasons ...string) {
-	markStatus(analysis, StatusFail, reasons...)
+// markFailure fails analysis with a reason formatted by newReason. The mark
+// helpers take the message as a template so that WithMessages translations keep
+// the placeholder values.
+func markFailure(analysis *NodeAnalysisResult, code, message string, args ...any) {
+	markStatus(analysis, StatusFail, newReason(code, message, args...))
 }
 
-func markWarning(analysis *NodeAnalysisResult, reasons ...string) {
-	markStatus(analysis, StatusWarning, reasons...)
+func markWarning(analysis *NodeAnalysisResult, code, message string, args ...any) {
+	markStatus(analysis, StatusWarning, newReason(code, message, args...))
 }
 
-func markInformational(analysis *NodeAnalysisResult, reasons ...string) {
-	markStatus(analysis, StatusInfo, reasons...)
+func markInformational(analysis *NodeAnalysisResult, code, message string, args ...any) {
+	markStatus(analysis, StatusInfo, newReason(code, message, args...))
 }
 
-func markRecommendation(analysis *NodeAnalysisResult, reasons ...string) {
-	markStatus(analysis, StatusRecommendation, reasons...)
+func markRecommendation(analysis *NodeAnalysisResult, code, message string, args ...any) {
+	markStatus(analysis, StatusRecommendation, newReason(code, message, args...))
 }
 
-func markStatus(analysis *NodeAnalysisResult, status ResultStatus, reasons ...string) {
+func markStatus(analysis *NodeAnalysisResult, status ResultStatus, reasons ...Reason) {
 	if analysis == nil {
 		return
 	}
@@ -553,7 +1303,7 @@ func markStatus(analysis *NodeAnalysisResult, status ResultStatus, reasons ...st
 		analysis.Status = current
 	}
 	for _, reason := range reasons {
-		if reason == "" {
+		if reason.Message == "" {
 			continue
 		}
 		analysis.Reasons = append(analysis.Reasons, reason)
@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateVMAP validates the VAST documents embedded in a VMAP playlist and
+// returns one result per AdBreak, in document order. A break's VAST is taken
+// from the VASTAdData of its AdSource or, failing that, from AdTagURI text that
+// holds a VAST document rather than a URL. Breaks that only reference a remote
+// ad tag are not fetched and have a nil result. Options are applied once and
+// shared by every embedded document, as in ValidateBatch.
+func ValidateVMAP(raw []byte, opts ...Option) ([]*ValidationResult, error) {
+	raw, err := decompressDocument(raw)
+	if err != nil {
+		return nil, newValidateError(ParseError, err)
+	}
+	if len(trimDocumentPrefix(raw)) == 0 {
+		return nil, newValidateError(ParseError, errEmptyXML)
+	}
+	root, err := buildNodeTree(raw)
+	if err != nil {
+		return nil, newValidateError(ParseError, err)
+	}
+	if !strings.EqualFold(root.localName(), "VMAP") {
+		return nil, newValidateError(RootError, ErrInvalidRoot)
+	}
+
+	base := newConfig(opts...)
+	var results []*ValidationResult
+	for _, adBreak := range root.Children {
+		if adBreak.localName() != "AdBreak" {
+			continue
+		}
+		doc := embeddedVAST(adBreak)
+		if doc == nil {
+			results = append(results, nil)
+			continue
+		}
+		cfg := *base
+		result, err := validateWithConfig(doc, &cfg)
+		if err != nil {
+			return nil, fmt.Errorf("validator: AdBreak %d: %w", len(results)+1, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// embeddedVAST returns the VAST document carried by an AdBreak's AdSource, or
+// nil when it only references a remote ad tag.
+func embeddedVAST(adBreak *genericNode) []byte {
+	for _, source := range adBreak.Children {
+		if source.localName() != "AdSource" {
+			continue
+		}
+		for _, child := range source.Children {
+			switch child.localName() {
+			case "VASTAdData":
+				for _, doc := range child.Children {
+					if strings.EqualFold(doc.localName(), "VAST") {
+						return doc.Raw
+					}
+				}
+			case "AdTagURI":
+				if text := strings.TrimSpace(child.Content); strings.HasPrefix(text, "<") {
+					return []byte(text)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -36,7 +36,7 @@ type UniversalAdID struct {
 // Link: https://iabtechlab.com/wp-content/uploads/2019/06/VAST_4.2_final_june26.pdf#page=41
 type InLine struct {
 	AdDefinition
-	AdServingID     string           `xml:"AdServingId"`
+	AdServingID     string           `xml:"AdServingId,omitempty"`
 	AdTitle         string           `xml:"AdTitle"`
 	AdVerifications *AdVerifications `xml:"AdVerifications,omitempty"`
 	Advertiser      string           `xml:"Advertiser,omitempty"`
@@ -39,6 +39,28 @@ type AdDefinition struct {
 	ViewableImpression *ViewableImpression `xml:"ViewableImpression,omitempty"`
 }
 
+// AdCount returns the number of Ad elements in the document, counting InLine
+// and Wrapper ads alike.
+func (v *VAST) AdCount() int {
+	if v == nil {
+		return 0
+	}
+	return len(v.Ad)
+}
+
+// CreativeCount returns the number of Creative elements across every InLine
+// and Wrapper ad in the document.
+func (v *VAST) CreativeCount() int {
+	count := 0
+	v.Walk(func(node any) {
+		switch node.(type) {
+		case *InLineCreative, *WrapperCreative:
+			count++
+		}
+	})
+	return count
+}
+
 // AdSystem identifies the ad server that returned the ad and optionally its version.
 // Reference: IAB VAST 4.x Section 2.3.1.3 - AdSystem Element
 // Link: https://iabtechlab.com/wp-content/uploads/2019/06/VAST_4.2_final_june26.pdf#page=41
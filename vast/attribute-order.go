@@ -0,0 +1,146 @@
+package vast
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+)
+
+// attributeOrder records the attribute names of an element as they appeared in
+// the source document, so Bytes can reproduce them in the same sequence.
+type attributeOrder []string
+
+// attributeOrdered is implemented by elements that record their source
+// attribute order, so Clone can copy the unexported field.
+type attributeOrdered interface {
+	sourceAttributeOrder() *attributeOrder
+}
+
+func (m *MediaFile) sourceAttributeOrder() *attributeOrder               { return &m.attrOrder }
+func (m *Mezzanine) sourceAttributeOrder() *attributeOrder               { return &m.attrOrder }
+func (f *InteractiveCreativeFile) sourceAttributeOrder() *attributeOrder { return &f.attrOrder }
+func (t *Tracking) sourceAttributeOrder() *attributeOrder                { return &t.attrOrder }
+
+func attributeNames(attrs []xml.Attr) attributeOrder {
+	if len(attrs) == 0 {
+		return nil
+	}
+	names := make(attributeOrder, len(attrs))
+	for i, attr := range attrs {
+		names[i] = attr.Name.Local
+	}
+	return names
+}
+
+// orderedLeaf renders a CDATA leaf element with an explicit attribute sequence.
+type orderedLeaf struct {
+	Attrs []xml.Attr `xml:",any,attr"`
+	Value string     `xml:",cdata"`
+}
+
+// encodeOrdered marshals plain, the alias of a leaf element type, emitting its
+// attributes in the recorded order. Attributes not present in the source (for
+// example ones set after Read) follow in struct field order.
+func encodeOrdered(e *xml.Encoder, start xml.StartElement, plain any, order attributeOrder, value string) error {
+	if len(order) == 0 {
+		return e.EncodeElement(plain, start)
+	}
+	raw, err := xml.Marshal(plain)
+	if err != nil {
+		return err
+	}
+	token, err := xml.NewDecoder(bytes.NewReader(raw)).Token()
+	if err != nil {
+		return err
+	}
+	rendered, ok := token.(xml.StartElement)
+	if !ok {
+		return errors.New("vast: unexpected token while ordering attributes")
+	}
+
+	attrs := make([]xml.Attr, 0, len(rendered.Attr))
+	used := make([]bool, len(rendered.Attr))
+	for _, name := range order {
+		for i, attr := range rendered.Attr {
+			if !used[i] && attr.Name.Local == name {
+				attrs = append(attrs, attr)
+				used[i] = true
+				break
+			}
+		}
+	}
+	for i, attr := range rendered.Attr {
+		if !used[i] {
+			attrs = append(attrs, attr)
+		}
+	}
+	return e.EncodeElement(orderedLeaf{Attrs: attrs, Value: value}, xml.StartElement{Name: start.Name})
+}
+
+// UnmarshalXML records the source attribute order.
+func (m *MediaFile) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain MediaFile
+	if err := d.DecodeElement((*plain)(m), &start); err != nil {
+		return err
+	}
+	m.attrOrder = attributeNames(start.Attr)
+	return nil
+}
+
+// MarshalXML emits attributes in their source order when the MediaFile was read
+// from a document.
+func (m MediaFile) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain MediaFile
+	return encodeOrdered(e, start, plain(m), m.attrOrder, m.Value)
+}
+
+// UnmarshalXML records the source attribute order.
+func (m *Mezzanine) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain Mezzanine
+	if err := d.DecodeElement((*plain)(m), &start); err != nil {
+		return err
+	}
+	m.attrOrder = attributeNames(start.Attr)
+	return nil
+}
+
+// MarshalXML emits attributes in their source order when the Mezzanine was read
+// from a document.
+func (m Mezzanine) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain Mezzanine
+	return encodeOrdered(e, start, plain(m), m.attrOrder, m.Value)
+}
+
+// UnmarshalXML records the source attribute order.
+func (f *InteractiveCreativeFile) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain InteractiveCreativeFile
+	if err := d.DecodeElement((*plain)(f), &start); err != nil {
+		return err
+	}
+	f.attrOrder = attributeNames(start.Attr)
+	return nil
+}
+
+// MarshalXML emits attributes in their source order when the file was read from
+// a document.
+func (f InteractiveCreativeFile) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain InteractiveCreativeFile
+	return encodeOrdered(e, start, plain(f), f.attrOrder, f.Value)
+}
+
+// UnmarshalXML records the source attribute order.
+func (t *Tracking) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plain Tracking
+	if err := d.DecodeElement((*plain)(t), &start); err != nil {
+		return err
+	}
+	t.attrOrder = attributeNames(start.Attr)
+	return nil
+}
+
+// MarshalXML emits attributes in their source order when the Tracking was read
+// from a document.
+func (t Tracking) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	type plain Tracking
+	return encodeOrdered(e, start, plain(t), t.attrOrder, t.Value)
+}
@@ -0,0 +1,91 @@
+package vast
+
+import (
+	"reflect"
+	"slices"
+)
+
+// Clone returns a deep copy of the document. Slices, pointers and CDATA values
+// are copied, so mutating the clone (for example with SubstituteMacros) never
+// affects the original. This is useful when rendering per-request variants from
+// a shared template.
+func (v *VAST) Clone() *VAST {
+	if v == nil {
+		return nil
+	}
+	clone := &VAST{}
+	deepCopy(reflect.ValueOf(clone).Elem(), reflect.ValueOf(v).Elem())
+	return clone
+}
+
+// deepCopy copies src into dst, allocating new backing storage for pointers,
+// slices and maps. Unexported fields are not settable through reflection; the
+// source attribute order recorded by Read is carried over by
+// cloneAttributeOrder so the clone renders like the original.
+func deepCopy(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.New(src.Elem().Type())
+		deepCopy(copied.Elem(), src.Elem())
+		dst.Set(copied)
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			if !dst.Field(i).CanSet() {
+				continue
+			}
+			deepCopy(dst.Field(i), src.Field(i))
+		}
+		cloneAttributeOrder(dst, src)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopy(copied.Index(i), src.Index(i))
+		}
+		dst.Set(copied)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		copied := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			deepCopy(value, iter.Value())
+			copied.SetMapIndex(iter.Key(), value)
+		}
+		dst.Set(copied)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		value := reflect.New(src.Elem().Type()).Elem()
+		deepCopy(value, src.Elem())
+		dst.Set(value)
+	default:
+		dst.Set(src)
+	}
+}
+
+// cloneAttributeOrder copies the recorded source attribute order from src to
+// dst when the element keeps one.
+func cloneAttributeOrder(dst, src reflect.Value) {
+	if !dst.CanAddr() {
+		return
+	}
+	target, ok := dst.Addr().Interface().(attributeOrdered)
+	if !ok {
+		return
+	}
+	if !src.CanAddr() {
+		addressable := reflect.New(src.Type()).Elem()
+		addressable.Set(src)
+		src = addressable
+	}
+	*target.sourceAttributeOrder() = slices.Clone(*src.Addr().Interface().(attributeOrdered).sourceAttributeOrder())
+}
@@ -1,6 +1,14 @@
 package vast
 
-import "errors"
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type NumericBool bool
 
@@ -22,6 +30,30 @@ type CData struct {
 	Value string `xml:",cdata"`
 }
 
+// UnmarshalXML concatenates every text and CDATA section of the element into
+// Value, so content split across both forms survives a round trip. Nested
+// elements are skipped.
+func (c *CData) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value strings.Builder
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.CharData:
+			value.Write(t)
+		case xml.StartElement:
+			if err := d.Skip(); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			c.Value = value.String()
+			return nil
+		}
+	}
+}
+
 // AdParameters contains ad-specific parameters passed to the ad creative.
 // Link: https://iabtechlab.com/wp-content/uploads/2019/06/VAST_4.2_final_june26.pdf#page=52
 type AdParameters struct {
@@ -41,9 +73,18 @@ func NewDuration(value string) (Duration, error) {
 }
 
 // Duration must be in the format hh:mm:ss, where hh is 00-23, mm is 00-59, and ss is 00-59.
+// An optional .mmm millisecond suffix is accepted.
 // The total duration must be at least 5 seconds (00:00:05) and cannot be 00:00:00.
 func (d Duration) ValidateDuration() error {
 	str := string(d)
+	if len(str) == 12 && str[8] == '.' {
+		for _, char := range str[9:] {
+			if char < '0' || char > '9' {
+				return errors.New("Duration must be in the format hh:mm:ss")
+			}
+		}
+		str = str[:8]
+	}
 	if len(str) != 8 {
 		return errors.New("Duration must be in the format hh:mm:ss")
 	}
@@ -59,8 +100,16 @@ func (d Duration) ValidateDuration() error {
 		}
 	}
 
-	// Validate ranges: hh (00-23), mm (00-59), ss (00-59)
+	// Get all values and convert them to a single integer
 	var numericValue int
+	for i, char := range str {
+		if i == 2 || i == 5 {
+			continue
+		}
+		numericValue = numericValue*10 + int(char-'0')
+	}
+
+	// Validate ranges: hh (00-23), mm (00-59), ss (00-59)
 	hours := numericValue / 10000
 	minutes := (numericValue / 100) % 100
 	seconds := numericValue % 100
@@ -75,13 +124,6 @@ func (d Duration) ValidateDuration() error {
 		return errors.New("seconds must be between 00 and 59")
 	}
 
-	// Get all values and convert them to a single integer
-	for i, char := range str {
-		if i == 2 || i == 5 {
-			continue
-		}
-		numericValue = numericValue*10 + int(char-'0')
-	}
 	// Check if the total duration is zero
 	if numericValue == 0 {
 		return errors.New("Duration cannot be 00:00:00")
@@ -93,6 +135,31 @@ func (d Duration) ValidateDuration() error {
 	return nil
 }
 
+// ToDuration parses the hh:mm:ss[.mmm] value into a time.Duration.
+func (d Duration) ToDuration() (time.Duration, error) {
+	return parseClockTime(string(d))
+}
+
+var clockTimePattern = regexp.MustCompile(`^(\d{2}):([0-5]\d):([0-5]\d)(\.\d{3})?$`)
+
+// parseClockTime converts a VAST time value (HH:MM:SS or HH:MM:SS.mmm) into a
+// time.Duration.
+func parseClockTime(value string) (time.Duration, error) {
+	match := clockTimePattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, fmt.Errorf("time %q must be in the format HH:MM:SS[.mmm]", value)
+	}
+	hours, _ := strconv.Atoi(match[1])
+	minutes, _ := strconv.Atoi(match[2])
+	seconds, _ := strconv.Atoi(match[3])
+	total := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if match[4] != "" {
+		millis, _ := strconv.Atoi(match[4][1:])
+		total += time.Duration(millis) * time.Millisecond
+	}
+	return total, nil
+}
+
 // XPosition constraints ([0-9]*|left|right).
 type XPosition string
 
@@ -0,0 +1,128 @@
+package vast
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ../cmd/vastversiongen -out versions_gen.go
+
+// versionRule records the first VAST version defining a child element or an
+// attribute, as listed in the validator catalog. Node is the catalog node
+// describing the child element, if any.
+type versionRule struct {
+	Since Version
+	Node  string
+}
+
+// ConvertTo returns a copy of the document targeting another VAST version along
+// with a description of every element or attribute dropped. Upgrades only set
+// the version attribute; downgrades remove every element and attribute the
+// validator catalog introduces after the target version, such as
+// AdVerifications, ViewableImpression and UniversalAdId when converting below
+// 4.0. The receiver is never modified.
+func (v *VAST) ConvertTo(target Version) (*VAST, []string) {
+	if v == nil {
+		return nil, nil
+	}
+	converted := v.Clone()
+	converted.Version = target
+	targetNumber, ok := versionNumber(target)
+	if !ok {
+		return converted, nil
+	}
+
+	var changes []string
+	below := func(minimum Version) bool {
+		floor, ok := versionNumber(minimum)
+		return ok && targetNumber < floor
+	}
+	report := func(path, element string, minimum Version) {
+		changes = append(changes, fmt.Sprintf("removed %s from %s (requires VAST %s)", element, path, minimum))
+	}
+
+	root := reflect.ValueOf(converted).Elem()
+	for i := range converted.Ad {
+		downgradeNode(root.FieldByName("Ad").Index(i), "Ad", fmt.Sprintf("Ad[%d]", i+1), below, report)
+	}
+	return converted, changes
+}
+
+// downgradeNode clears the children and attributes of value, an element
+// described by the catalog node key, that the target version does not define,
+// then recurses into the remaining children.
+func downgradeNode(value reflect.Value, key, path string, below func(Version) bool, report func(path, element string, minimum Version)) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return
+	}
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Name == "XMLName" {
+			continue
+		}
+		tag, hasTag := field.Tag.Lookup("xml")
+		if field.Anonymous && !hasTag {
+			// Embedded types such as AdDefinition share the element of their parent.
+			downgradeNode(value.Field(i), key, path, below, report)
+			continue
+		}
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		if name == "-" || strings.Contains(flags, "chardata") || strings.Contains(flags, "cdata") ||
+			strings.Contains(flags, "innerxml") || strings.Contains(flags, "any") {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		if strings.Contains(flags, "attr") {
+			rule, ok := catalogVersionRules[key+"@"+name]
+			if ok && below(rule.Since) && !fieldValue.IsZero() {
+				fieldValue.SetZero()
+				report(path, name, rule.Since)
+			}
+			continue
+		}
+
+		rule, ok := catalogVersionRules[key+">"+name]
+		if !ok {
+			continue
+		}
+		if below(rule.Since) {
+			if !fieldValue.IsZero() {
+				fieldValue.SetZero()
+				report(path, name, rule.Since)
+			}
+			continue
+		}
+		if rule.Node == "" {
+			continue
+		}
+		if fieldValue.Kind() == reflect.Slice {
+			for j := 0; j < fieldValue.Len(); j++ {
+				downgradeNode(fieldValue.Index(j), rule.Node, fmt.Sprintf("%s/%s[%d]", path, name, j+1), below, report)
+			}
+			continue
+		}
+		downgradeNode(fieldValue, rule.Node, path+"/"+name, below, report)
+	}
+}
+
+// versionNumber parses a version such as "4.2" for ordering comparisons.
+func versionNumber(version Version) (float64, bool) {
+	number, err := strconv.ParseFloat(string(version), 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
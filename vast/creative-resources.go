@@ -21,6 +21,8 @@ type InteractiveCreativeFile struct {
 	Type             string      `xml:"type,attr,omitempty"`
 	APIFramework     string      `xml:"apiFramework,attr,omitempty"`
 	VariableDuration NumericBool `xml:"variableDuration,attr,omitempty"`
+
+	attrOrder attributeOrder
 }
 
 // ExecutableResource represents an executable resource for ad verification.
@@ -1,5 +1,7 @@
 package vast
 
+import "encoding/xml"
+
 // Creatives contains a collection of creative elements for wrapper ads.
 // Holds multiple creative definitions within a wrapper ad structure.
 //
@@ -35,7 +37,28 @@ type CreativeExtensions struct {
 //
 // Reference: IAB VAST 4.x Section 2.3.2.6 - CreativeExtensions Element
 // Link: https://iabtechlab.com/wp-content/uploads/2019/06/VAST_4.2_final_june26.pdf#page=50
+//
+// RawExtras holds the element's inner XML as read from a document, so vendor
+// nodes the struct does not model survive a round trip. When set it is written
+// back verbatim in place of Items.
 type CreativeExtension struct {
-	Items []string `xml:",any"`
-	Type  string   `xml:"type,attr,omitempty"`
+	Items     []string `xml:",any"`
+	Type      string   `xml:"type,attr,omitempty"`
+	RawExtras []byte   `xml:",innerxml"`
+}
+
+// MarshalXML writes RawExtras unchanged when present and Items otherwise.
+func (c CreativeExtension) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(c.RawExtras) == 0 {
+		type plain struct {
+			Items []string `xml:",any"`
+			Type  string   `xml:"type,attr,omitempty"`
+		}
+		return e.EncodeElement(plain{Items: c.Items, Type: c.Type}, start)
+	}
+	raw := struct {
+		Type  string `xml:"type,attr,omitempty"`
+		Inner []byte `xml:",innerxml"`
+	}{Type: c.Type, Inner: c.RawExtras}
+	return e.EncodeElement(raw, start)
 }
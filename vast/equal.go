@@ -0,0 +1,96 @@
+package vast
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Equal reports whether two documents are structurally equivalent. The typed
+// trees are compared field by field, so attribute order is irrelevant; leading
+// and trailing whitespace in text is ignored, and a nil pointer or slice is
+// treated the same as an empty one.
+func Equal(a, b *VAST) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return equalValues(reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem())
+}
+
+func equalValues(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return isZeroValue(a) && isZeroValue(b)
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !a.Type().Field(i).IsExported() {
+				continue
+			}
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			other := b.MapIndex(iter.Key())
+			if !other.IsValid() || !equalValues(iter.Value(), other) {
+				return false
+			}
+		}
+		return true
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Elem().Type() != b.Elem().Type() {
+			return false
+		}
+		return equalValues(a.Elem(), b.Elem())
+	case reflect.String:
+		return strings.TrimSpace(a.String()) == strings.TrimSpace(b.String())
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
+
+// isZeroValue reports whether v holds no content once nil pointers, empty
+// slices and whitespace-only text are normalized.
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil() || isZeroValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			if !isZeroValue(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	default:
+		return v.IsZero()
+	}
+}
@@ -2,8 +2,11 @@ package vast
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Linear represents the base structure for linear video advertisements.
@@ -76,3 +79,75 @@ func (s SkipOffset) Validate() error {
 
 	return errors.New("SkipOffset must match pattern (HH:MM:SS[.fff] or percentage)")
 }
+
+// Resolve returns the absolute offset at which the skip control should appear.
+// Percentage offsets are computed relative to d; time offsets are parsed directly
+// and d is ignored.
+func (s SkipOffset) Resolve(d Duration) (time.Duration, error) {
+	if err := s.Validate(); err != nil {
+		return 0, err
+	}
+	str := string(s)
+	if str == "" {
+		return 0, errors.New("SkipOffset is empty")
+	}
+	if !strings.HasSuffix(str, "%") {
+		return parseClockTime(str)
+	}
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(str, "%"), 64)
+	if err != nil || percent > 100 {
+		return 0, fmt.Errorf("SkipOffset %q is not a valid percentage", str)
+	}
+	total, err := d.ToDuration()
+	if err != nil {
+		return 0, fmt.Errorf("resolve SkipOffset %q: %w", str, err)
+	}
+	return time.Duration(float64(total) * percent / 100).Round(time.Millisecond), nil
+}
+
+// PrimaryDuration returns the Duration of the first InLine Linear creative. The
+// ok flag is false when there is no such creative or its Duration is malformed.
+func (v *VAST) PrimaryDuration() (time.Duration, bool) {
+	if v == nil {
+		return 0, false
+	}
+	for _, ad := range v.Ad {
+		if ad.InLine == nil {
+			continue
+		}
+		for _, creative := range ad.InLine.Creatives.Creative {
+			if creative.Linear == nil {
+				continue
+			}
+			duration, err := creative.Linear.Duration.ToDuration()
+			return duration, err == nil
+		}
+	}
+	return 0, false
+}
+
+// TotalDuration sums the Durations of every InLine Linear creative, e.g. the
+// playback length of an ad pod. Wrapper ads are skipped because their duration
+// is only known once the chain is resolved.
+func (v *VAST) TotalDuration() (time.Duration, error) {
+	if v == nil {
+		return 0, nil
+	}
+	var total time.Duration
+	for i, ad := range v.Ad {
+		if ad.InLine == nil {
+			continue
+		}
+		for _, creative := range ad.InLine.Creatives.Creative {
+			if creative.Linear == nil {
+				continue
+			}
+			duration, err := creative.Linear.Duration.ToDuration()
+			if err != nil {
+				return 0, fmt.Errorf("Ad[%d]: %w", i+1, err)
+			}
+			total += duration
+		}
+	}
+	return total, nil
+}
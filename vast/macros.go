@@ -0,0 +1,90 @@
+package vast
+
+import "strings"
+
+// SubstituteMacros replaces [MACRO] tokens in the URL-bearing fields of the
+// document (impressions, tracking events, clicks, error beacons, VASTAdTagURI,
+// media files, closed captions, verification scripts and static, IFrame and HTML
+// resources) with the supplied values and returns the number of tokens replaced.
+// Keys may be given with or without brackets, e.g. "CACHEBUSTING" or "[CACHEBUSTING]".
+// Macros without a value are left intact for the player to expand.
+func (v *VAST) SubstituteMacros(values map[string]string) int {
+	if v == nil || len(values) == 0 {
+		return 0
+	}
+	macros := make(map[string]string, len(values))
+	for key, value := range values {
+		macros[strings.Trim(key, "[]")] = value
+	}
+	count := 0
+	v.Walk(func(node any) {
+		switch n := node.(type) {
+		case *Impression:
+			count += substituteMacros(&n.Value, macros)
+		case *Tracking:
+			count += substituteMacros(&n.Value, macros)
+		case *ClickThrough:
+			count += substituteMacros(&n.Value, macros)
+		case *MediaFile:
+			count += substituteMacros(&n.Value, macros)
+		case *Mezzanine:
+			count += substituteMacros(&n.Value, macros)
+		case *InteractiveCreativeFile:
+			count += substituteMacros(&n.Value, macros)
+		case *ClosedCaptionFile:
+			count += substituteMacros(&n.Value, macros)
+		case *StaticResource:
+			count += substituteMacros(&n.Value, macros)
+		case *IFrameResource:
+			count += substituteMacros(&n.Value, macros)
+		case *HTMLResource:
+			count += substituteMacros(&n.Value, macros)
+		case *JavaScriptResource:
+			count += substituteMacros(&n.Value, macros)
+		case *ExecutableResource:
+			count += substituteMacros(&n.Value, macros)
+		case *CData:
+			count += substituteMacros(&n.Value, macros)
+		case *string:
+			count += substituteMacros(n, macros)
+		}
+	})
+	return count
+}
+
+// substituteMacros rewrites the [MACRO] tokens in value that have an entry in macros.
+func substituteMacros(value *string, macros map[string]string) int {
+	if !strings.Contains(*value, "[") {
+		return 0
+	}
+	var b strings.Builder
+	count := 0
+	rest := *value
+	for {
+		start := strings.IndexByte(rest, '[')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], ']')
+		if end < 0 {
+			break
+		}
+		end += start
+		replacement, ok := macros[rest[start+1:end]]
+		if !ok {
+			b.WriteString(rest[:start+1])
+			rest = rest[start+1:]
+			continue
+		}
+		b.WriteString(rest[:start])
+		b.WriteString(replacement)
+		rest = rest[end+1:]
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	b.WriteString(rest)
+	*value = b.String()
+	return count
+}
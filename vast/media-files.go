@@ -1,5 +1,10 @@
 package vast
 
+import (
+	"sort"
+	"strings"
+)
+
 // Delivery specifies the method of media content delivery to the player.
 //
 // Reference: IAB VAST 4.x Section 2.3.2.3 - MediaFile Element
@@ -44,6 +49,8 @@ type MediaFile struct {
 	FileSize            int         `xml:"fileSize,attr,omitempty"`
 	MediaType           string      `xml:"mediaType,attr,omitempty"`
 	APIFramework        string      `xml:"apiFramework,attr,omitempty"`
+
+	attrOrder attributeOrder
 }
 
 // Mezzanine represents a high-quality source file for transcoding purposes.
@@ -62,4 +69,69 @@ type Mezzanine struct {
 	Codec     string   `xml:"codec,attr,omitempty"`
 	FileSize  int      `xml:"fileSize,attr,omitempty"`
 	MediaType string   `xml:"mediaType,attr,omitempty"`
+
+	attrOrder attributeOrder
+}
+
+// MediaFilesByBitrate returns a copy of every MediaFile across all ads and
+// creatives sorted by ascending bitrate. Files without a bitrate are placed last;
+// ties keep document order.
+func (v *VAST) MediaFilesByBitrate() []MediaFile {
+	var files []MediaFile
+	v.Walk(func(node any) {
+		if file, ok := node.(*MediaFile); ok {
+			files = append(files, *file)
+		}
+	})
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i].Bitrate, files[j].Bitrate
+		if a == 0 || b == 0 {
+			return b == 0 && a != 0
+		}
+		return a < b
+	})
+	return files
+}
+
+// SelectMediaFile returns the inline MediaFile a player would pick: the one with
+// the highest bitrate not above maxBitrate (kbps) among files whose type is one
+// of mimeTypes. A maxBitrate of 0 or less sets no cap and empty mimeTypes accepts
+// any type; type matching is case-insensitive. Files declaring only a bitrate
+// range are compared by minBitrate, files without a bitrate rank below all
+// others, and files without a URL are skipped. Ties keep document order. The
+// returned pointer refers into the document.
+func (v *VAST) SelectMediaFile(maxBitrate int, mimeTypes []string) (*MediaFile, bool) {
+	var best *MediaFile
+	bestBitrate := -1
+	v.Walk(func(node any) {
+		file, ok := node.(*MediaFile)
+		if !ok || strings.TrimSpace(file.Value) == "" || !mediaTypeAccepted(file.Type, mimeTypes) {
+			return
+		}
+		bitrate := file.Bitrate
+		if bitrate == 0 {
+			bitrate = file.MinBitrate
+		}
+		if maxBitrate > 0 && bitrate > maxBitrate {
+			return
+		}
+		if bitrate > bestBitrate {
+			best, bestBitrate = file, bitrate
+		}
+	})
+	return best, best != nil
+}
+
+// mediaTypeAccepted reports whether mimeType is in accepted, or accepted is empty.
+func mediaTypeAccepted(mimeType string, accepted []string) bool {
+	if len(accepted) == 0 {
+		return true
+	}
+	mimeType = strings.TrimSpace(mimeType)
+	for _, candidate := range accepted {
+		if strings.EqualFold(mimeType, strings.TrimSpace(candidate)) {
+			return true
+		}
+	}
+	return false
 }
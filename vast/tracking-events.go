@@ -69,6 +69,8 @@ type Tracking struct {
 	Value  string `xml:",cdata"`
 	Event  string `xml:"event,attr"`
 	Offset Offset `xml:"offset,attr,omitempty"`
+
+	attrOrder attributeOrder
 }
 
 // TrackingEventsVerification contains tracking events specific to ad verification.
@@ -87,3 +89,67 @@ type Impression struct {
 	Value string `xml:",cdata"`
 	ID    string `xml:"id,attr,omitempty"`
 }
+
+// ImpressionEventKey is the synthetic event name TrackingURLs uses for impressions.
+const ImpressionEventKey = "impression"
+
+// TrackingURLs returns every tracking beacon in the document grouped by event,
+// covering linear, nonlinear, companion and verification tracking. Impressions
+// are grouped under ImpressionEventKey. Duplicates are preserved in document order.
+func (v *VAST) TrackingURLs() map[string][]string {
+	urls := map[string][]string{}
+	v.Walk(func(node any) {
+		switch n := node.(type) {
+		case *Impression:
+			urls[ImpressionEventKey] = append(urls[ImpressionEventKey], n.Value)
+		case *Tracking:
+			urls[n.Event] = append(urls[n.Event], n.Value)
+		}
+	})
+	return urls
+}
+
+// StripTracking removes every tracking beacon from the document for serving in
+// privacy-restricted contexts: impressions, viewable-impression URLs, linear,
+// nonlinear, companion and verification tracking events, and click, custom-click
+// and icon tracking. Creatives, media files, resources, click-throughs and Error
+// URLs are left intact.
+func (v *VAST) StripTracking() {
+	v.Walk(func(node any) {
+		switch n := node.(type) {
+		case *InLine:
+			n.Impression = nil
+			n.ViewableImpression = nil
+		case *Wrapper:
+			n.Impression = nil
+			n.ViewableImpression = nil
+		case *Verification:
+			n.TrackingEvents = nil
+		case *InLineCreative:
+			if n.NonLinearAds != nil {
+				n.NonLinearAds.TrackingEvents = nil
+			}
+		case *WrapperCreative:
+			if n.NonLinearAds != nil {
+				n.NonLinearAds.TrackingEvents = nil
+			}
+		case *LinearInLine:
+			n.TrackingEvents = nil
+		case *LinearWrapper:
+			n.TrackingEvents = nil
+		case *VideoClicks:
+			n.ClickTracking = nil
+			n.CustomClick = nil
+		case *NonLinearAd:
+			n.NonLinearClickTracking = nil
+		case *CompanionAd:
+			n.CompanionClickTracking = nil
+			n.TrackingEvents = nil
+		case *Icon:
+			n.IconViewTracking = nil
+			if n.IconClicks != nil {
+				n.IconClicks.IconClickTracking = nil
+			}
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"io"
+	"strings"
 )
 
 // VAST represents the root element of a VAST document containing ads and metadata.
@@ -61,3 +62,24 @@ func Read(reader io.ReadCloser) (*VAST, error) {
 
 	return vast, nil
 }
+
+// Parse decodes a VAST document held in memory. Errors wrap ErrUnmarshalVAST,
+// as with Read.
+func Parse(raw []byte) (*VAST, error) {
+	return Read(io.NopCloser(bytes.NewReader(raw)))
+}
+
+// ParseString decodes a VAST document held in a string.
+func ParseString(s string) (*VAST, error) {
+	return Read(io.NopCloser(strings.NewReader(s)))
+}
+
+// Reformat parses raw VAST XML and re-emits it with consistent indentation.
+// CDATA sections are preserved; malformed input returns an error.
+func Reformat(raw []byte) ([]byte, error) {
+	doc, err := Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Bytes()
+}
@@ -0,0 +1,889 @@
+package vast
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+const walkFixture = `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Impression id="imp-1"><![CDATA[https://example.com/imp?a=1]]></Impression>
+      <Impression id="imp-2"><![CDATA[https://example.com/imp?a=2]]></Impression>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <TrackingEvents>
+              <Tracking event="start"><![CDATA[https://example.com/start]]></Tracking>
+            </TrackingEvents>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://example.com/video.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`
+
+func readFixture(t *testing.T, raw string) *VAST {
+	t.Helper()
+	doc, err := Read(io.NopCloser(strings.NewReader(raw)))
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	return doc
+}
+
+func TestVAST_WalkMutatesImpressions(t *testing.T) {
+	doc := readFixture(t, walkFixture)
+
+	visited := map[string]int{}
+	doc.Walk(func(node any) {
+		switch n := node.(type) {
+		case *Impression:
+			visited["Impression"]++
+			n.Value = strings.ToUpper(n.Value)
+		case *Tracking:
+			visited["Tracking"]++
+		case *MediaFile:
+			visited["MediaFile"]++
+		}
+	})
+	if visited["Impression"] != 2 || visited["Tracking"] != 1 || visited["MediaFile"] != 1 {
+		t.Fatalf("unexpected visit counts: %v", visited)
+	}
+
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	for _, want := range []string{"HTTPS://EXAMPLE.COM/IMP?A=1", "HTTPS://EXAMPLE.COM/IMP?A=2"} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("expected marshaled output to contain %s:\n%s", want, out)
+		}
+	}
+	if !strings.Contains(string(out), "https://example.com/start") {
+		t.Fatalf("expected tracking URL to be left untouched:\n%s", out)
+	}
+}
+
+func TestVAST_SubstituteMacros(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+  <Error><![CDATA[https://example.com/error?code=[ERRORCODE]&cb=[CACHEBUSTING]]]></Error>
+  <Ad id="1">
+    <Wrapper>
+      <AdSystem>Example</AdSystem>
+      <Impression><![CDATA[https://example.com/imp?cb=[CACHEBUSTING]&ts=[TIMESTAMP]]]></Impression>
+      <VASTAdTagURI><![CDATA[https://example.com/vast?cb=[CACHEBUSTING]]]></VASTAdTagURI>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <TrackingEvents>
+              <Tracking event="start"><![CDATA[https://example.com/start?cb=[CACHEBUSTING]]]></Tracking>
+            </TrackingEvents>
+            <VideoClicks>
+              <ClickTracking><![CDATA[https://example.com/click?cb=[CACHEBUSTING]]]></ClickTracking>
+            </VideoClicks>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </Wrapper>
+  </Ad>
+</VAST>`)
+
+	if got := doc.SubstituteMacros(map[string]string{"CACHEBUSTING": "12345678"}); got != 5 {
+		t.Fatalf("expected 5 replacements, got %d", got)
+	}
+	wrapper := doc.Ad[0].Wrapper
+	if want := "https://example.com/imp?cb=12345678&ts=[TIMESTAMP]"; wrapper.Impression[0].Value != want {
+		t.Fatalf("expected impression %s, got %s", want, wrapper.Impression[0].Value)
+	}
+	if want := "https://example.com/vast?cb=12345678"; wrapper.VASTAdTagURI.Value != want {
+		t.Fatalf("expected VASTAdTagURI %s, got %s", want, wrapper.VASTAdTagURI.Value)
+	}
+	if want := "https://example.com/error?code=[ERRORCODE]&cb=12345678"; doc.Error[0].Value != want {
+		t.Fatalf("expected error URL %s, got %s", want, doc.Error[0].Value)
+	}
+
+	if got := doc.SubstituteMacros(map[string]string{"[TIMESTAMP]": "2024-01-01T00:00:00Z", "ERRORCODE": "303"}); got != 2 {
+		t.Fatalf("expected 2 replacements, got %d", got)
+	}
+	if got := doc.SubstituteMacros(map[string]string{"CACHEBUSTING": "1"}); got != 0 {
+		t.Fatalf("expected no replacements once macros are expanded, got %d", got)
+	}
+}
+
+func TestVAST_SubstituteMacrosInResources(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://example.com/video.mp4?cb=[CACHEBUSTING]]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+        <Creative>
+          <CompanionAds>
+            <Companion width="300" height="250">
+              <StaticResource creativeType="image/png"><![CDATA[https://example.com/companion.png?cb=[CACHEBUSTING]]]></StaticResource>
+            </Companion>
+          </CompanionAds>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`)
+
+	if got := doc.SubstituteMacros(map[string]string{"CACHEBUSTING": "42"}); got != 2 {
+		t.Fatalf("expected 2 replacements, got %d", got)
+	}
+	creatives := doc.Ad[0].InLine.Creatives.Creative
+	if want := "https://example.com/video.mp4?cb=42"; creatives[0].Linear.MediaFiles.MediaFile[0].Value != want {
+		t.Fatalf("expected MediaFile %s, got %s", want, creatives[0].Linear.MediaFiles.MediaFile[0].Value)
+	}
+	if want := "https://example.com/companion.png?cb=42"; creatives[1].CompanionAds.Companion[0].StaticResource[0].Value != want {
+		t.Fatalf("expected StaticResource %s, got %s", want, creatives[1].CompanionAds.Companion[0].StaticResource[0].Value)
+	}
+}
+
+func TestVAST_Clone(t *testing.T) {
+	original := readFixture(t, walkFixture)
+	clone := original.Clone()
+
+	clone.Ad[0].InLine.Impression[0].Value = "https://example.com/changed"
+	clone.Ad[0].InLine.Creatives.Creative[0].Linear.TrackingEvents.Tracking[0].Value = "https://example.com/changed"
+	clone.Ad[0].InLine.Impression = append(clone.Ad[0].InLine.Impression, Impression{Value: "https://example.com/extra"})
+
+	inline := original.Ad[0].InLine
+	if got := inline.Impression[0].Value; got != "https://example.com/imp?a=1" {
+		t.Fatalf("expected original impression to be unchanged, got %s", got)
+	}
+	if got := len(inline.Impression); got != 2 {
+		t.Fatalf("expected original to keep 2 impressions, got %d", got)
+	}
+	if got := inline.Creatives.Creative[0].Linear.TrackingEvents.Tracking[0].Value; got != "https://example.com/start" {
+		t.Fatalf("expected original tracking to be unchanged, got %s", got)
+	}
+
+	if (*VAST)(nil).Clone() != nil {
+		t.Fatalf("expected nil clone of nil VAST")
+	}
+}
+
+func TestVAST_CloneKeepsAttributeOrder(t *testing.T) {
+	source := `<?xml version="1.0" encoding="UTF-8"?>
+<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <Impression id="imp-1"><![CDATA[https://example.com/imp]]></Impression>
+      <AdServingId>serving-1</AdServingId>
+      <AdTitle>Example</AdTitle>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <TrackingEvents>
+              <Tracking offset="00:00:05" event="progress"><![CDATA[https://example.com/progress]]></Tracking>
+            </TrackingEvents>
+            <Duration>00:00:15</Duration>
+            <MediaFiles>
+              <MediaFile width="640" height="360" type="video/mp4" delivery="progressive"><![CDATA[https://example.com/video.mp4]]></MediaFile>
+              <Mezzanine type="video/mp4" height="1080" width="1920" delivery="progressive"><![CDATA[https://example.com/mezz.mp4]]></Mezzanine>
+              <InteractiveCreativeFile type="text/html" apiFramework="SIMID"><![CDATA[https://example.com/simid.html]]></InteractiveCreativeFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`
+	doc, err := ParseString(source)
+	if err != nil {
+		t.Fatalf("ParseString returned error: %v", err)
+	}
+	out, err := doc.Clone().Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if string(out) != source {
+		t.Fatalf("expected the clone to render like the source, got:\n%s", out)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	base := readFixture(t, walkFixture)
+
+	reordered := readFixture(t, `<VAST version="4.2"><Ad id="1"><InLine>
+<AdSystem>Example</AdSystem><AdTitle>  Example  </AdTitle>
+<Impression id="imp-1"><![CDATA[https://example.com/imp?a=1]]></Impression>
+<Impression id="imp-2"><![CDATA[https://example.com/imp?a=2]]></Impression>
+<Creatives><Creative><Linear><Duration>00:00:15</Duration>
+<TrackingEvents><Tracking event="start"><![CDATA[https://example.com/start]]></Tracking></TrackingEvents>
+<MediaFiles><MediaFile height="360" width="640" type="video/mp4" delivery="progressive">
+  <![CDATA[https://example.com/video.mp4]]>
+</MediaFile></MediaFiles>
+</Linear></Creative></Creatives></InLine></Ad></VAST>`)
+	if !Equal(base, reordered) {
+		t.Fatalf("expected documents differing only by attribute order and whitespace to be equal")
+	}
+
+	changed := base.Clone()
+	changed.Ad[0].InLine.Impression[1].Value = "https://example.com/imp?a=3"
+	if Equal(base, changed) {
+		t.Fatalf("expected documents with different impression URLs to differ")
+	}
+
+	empty := base.Clone()
+	empty.Error = []CData{}
+	if !Equal(base, empty) {
+		t.Fatalf("expected nil and empty slices to compare equal")
+	}
+
+	if !Equal(nil, nil) || Equal(base, nil) {
+		t.Fatalf("unexpected nil handling")
+	}
+}
+
+func TestReformat(t *testing.T) {
+	minified := `<VAST version="4.2"><Ad id="1"><InLine><AdSystem>Example</AdSystem><AdTitle>Example</AdTitle><Impression id="imp-1"><![CDATA[https://example.com/imp?a=1&b=2]]></Impression><Creatives><Creative><Linear><Duration>00:00:15</Duration><MediaFiles><MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://example.com/video.mp4]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`
+
+	out, err := Reformat([]byte(minified))
+	if err != nil {
+		t.Fatalf("Reformat returned error: %v", err)
+	}
+	if !strings.Contains(string(out), "\n  <Ad") {
+		t.Fatalf("expected indented output, got:\n%s", out)
+	}
+	if !strings.Contains(string(out), "<![CDATA[https://example.com/imp?a=1&b=2]]>") {
+		t.Fatalf("expected CDATA to be preserved, got:\n%s", out)
+	}
+	if !Equal(readFixture(t, minified), readFixture(t, string(out))) {
+		t.Fatalf("expected reformatted document to be semantically equal to the input")
+	}
+
+	if _, err := Reformat([]byte(`<VAST version="4.2"><Ad>`)); err == nil {
+		t.Fatalf("expected malformed input to return an error")
+	}
+}
+
+func TestVAST_TrackingURLs(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Impression><![CDATA[https://example.com/imp1]]></Impression>
+      <Impression><![CDATA[https://example.com/imp2]]></Impression>
+      <AdVerifications>
+        <Verification vendor="example">
+          <TrackingEvents>
+            <Tracking event="verificationNotExecuted"><![CDATA[https://example.com/verification]]></Tracking>
+          </TrackingEvents>
+        </Verification>
+      </AdVerifications>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <TrackingEvents>
+              <Tracking event="start"><![CDATA[https://example.com/start]]></Tracking>
+              <Tracking event="complete"><![CDATA[https://example.com/complete]]></Tracking>
+              <Tracking event="start"><![CDATA[https://example.com/start]]></Tracking>
+            </TrackingEvents>
+          </Linear>
+        </Creative>
+        <Creative>
+          <CompanionAds>
+            <Companion width="300" height="250">
+              <TrackingEvents>
+                <Tracking event="creativeView"><![CDATA[https://example.com/companion-view]]></Tracking>
+              </TrackingEvents>
+            </Companion>
+          </CompanionAds>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`)
+
+	urls := doc.TrackingURLs()
+	want := map[string][]string{
+		ImpressionEventKey:        {"https://example.com/imp1", "https://example.com/imp2"},
+		"start":                   {"https://example.com/start", "https://example.com/start"},
+		"complete":                {"https://example.com/complete"},
+		"creativeView":            {"https://example.com/companion-view"},
+		"verificationNotExecuted": {"https://example.com/verification"},
+	}
+	if len(urls) != len(want) {
+		t.Fatalf("expected %d event groups, got %v", len(want), urls)
+	}
+	for event, expected := range want {
+		if strings.Join(urls[event], ",") != strings.Join(expected, ",") {
+			t.Fatalf("expected %s URLs %v, got %v", event, expected, urls[event])
+		}
+	}
+}
+
+func TestVAST_MediaFilesByBitrate(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="1920" height="1080" bitrate="4500"><![CDATA[https://example.com/1080.mp4]]></MediaFile>
+              <MediaFile delivery="streaming" type="application/x-mpegURL" width="0" height="0"><![CDATA[https://example.com/master.m3u8]]></MediaFile>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360" bitrate="800"><![CDATA[https://example.com/360.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+  <Ad id="2">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="1280" height="720" bitrate="2000"><![CDATA[https://example.com/720.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`)
+
+	files := doc.MediaFilesByBitrate()
+	var got []string
+	for _, file := range files {
+		got = append(got, file.Value)
+	}
+	want := []string{
+		"https://example.com/360.mp4",
+		"https://example.com/720.mp4",
+		"https://example.com/1080.mp4",
+		"https://example.com/master.m3u8",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+
+	files[0].Value = "changed"
+	if doc.Ad[0].InLine.Creatives.Creative[0].Linear.MediaFiles.MediaFile[2].Value != "https://example.com/360.mp4" {
+		t.Fatalf("expected returned media files to be copies")
+	}
+}
+
+func TestVAST_ConvertTo(t *testing.T) {
+	original := readFixture(t, `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Impression><![CDATA[https://example.com/imp]]></Impression>
+      <AdVerifications>
+        <Verification vendor="example">
+          <JavaScriptResource apiFramework="omid"><![CDATA[https://example.com/omid.js]]></JavaScriptResource>
+        </Verification>
+      </AdVerifications>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://example.com/video.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`)
+
+	downgraded, changes := original.ConvertTo(Version30)
+	if downgraded.Version != Version30 {
+		t.Fatalf("expected version 3.0, got %s", downgraded.Version)
+	}
+	if downgraded.Ad[0].InLine.AdVerifications != nil {
+		t.Fatalf("expected AdVerifications to be removed")
+	}
+	if len(changes) != 1 || !strings.Contains(changes[0], "AdVerifications") || !strings.Contains(changes[0], "Ad[1]/InLine") {
+		t.Fatalf("expected AdVerifications removal to be reported, got %v", changes)
+	}
+	out, err := downgraded.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if strings.Contains(string(out), "AdVerifications") {
+		t.Fatalf("expected marshaled output without AdVerifications:\n%s", out)
+	}
+	if original.Version != "4.2" || original.Ad[0].InLine.AdVerifications == nil {
+		t.Fatalf("expected the original document to be unchanged")
+	}
+
+	upgraded, changes := downgraded.ConvertTo(Version42)
+	if upgraded.Version != Version42 || len(changes) != 0 {
+		t.Fatalf("expected upgrade to only set the version, got %s %v", upgraded.Version, changes)
+	}
+	expected := downgraded.Clone()
+	expected.Version = Version42
+	if !Equal(upgraded, expected) {
+		t.Fatalf("expected upgrade to leave structure intact")
+	}
+}
+
+func TestSkipOffset_Resolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		offset   SkipOffset
+		duration Duration
+		want     time.Duration
+		wantErr  bool
+	}{
+		{name: "percentage", offset: "50%", duration: "00:00:10", want: 5 * time.Second},
+		{name: "time", offset: "00:00:05", duration: "00:00:30", want: 5 * time.Second},
+		{name: "time with millis", offset: "00:00:05.250", want: 5250 * time.Millisecond},
+		{name: "percentage invalid duration", offset: "25%", duration: "10s", wantErr: true},
+		{name: "percentage over 100", offset: "150%", duration: "00:00:10", wantErr: true},
+		{name: "invalid offset", offset: "5s", duration: "00:00:10", wantErr: true},
+		{name: "empty", offset: "", duration: "00:00:10", wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.offset.Resolve(tc.duration)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve returned error: %v", err)
+			}
+			if got != tc.want {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNewWrapperAd(t *testing.T) {
+	ad, err := NewWrapperAd("wrapper-1").
+		WithAdSystem("Example", "1.0").
+		AddImpression("https://example.com/imp").
+		WithTagURI("https://example.com/vast.xml").
+		SetFollowAdditionalWrappers(true).
+		Build()
+	if err != nil {
+		t.Fatalf("Build returned error: %v", err)
+	}
+	if ad.ID != "wrapper-1" || ad.Wrapper == nil {
+		t.Fatalf("unexpected ad: %+v", ad)
+	}
+	if ad.Wrapper.VASTAdTagURI.Value != "https://example.com/vast.xml" {
+		t.Fatalf("unexpected tag URI %q", ad.Wrapper.VASTAdTagURI.Value)
+	}
+	if !bool(ad.Wrapper.FollowAdditionalWrappers) {
+		t.Fatalf("expected followAdditionalWrappers to be set")
+	}
+
+	if _, err := NewWrapperAd("wrapper-2").WithAdSystem("Example", "").Build(); !errors.Is(err, ErrMissingTagURI) {
+		t.Fatalf("expected ErrMissingTagURI, got %v", err)
+	}
+	if _, err := NewWrapperAd("wrapper-3").WithTagURI("   ").Build(); !errors.Is(err, ErrMissingTagURI) {
+		t.Fatalf("expected ErrMissingTagURI for blank URI, got %v", err)
+	}
+	if _, err := NewWrapperAd("wrapper-4").WithTagURI("vast.xml").Build(); err == nil {
+		t.Fatalf("expected error for relative tag URI")
+	}
+}
+
+func TestVAST_PrimaryAndTotalDuration(t *testing.T) {
+	doc := readFixture(t, walkFixture)
+	got, ok := doc.PrimaryDuration()
+	if !ok || got != 15*time.Second {
+		t.Fatalf("expected primary duration 15s, got %v (ok=%v)", got, ok)
+	}
+	total, err := doc.TotalDuration()
+	if err != nil || total != 15*time.Second {
+		t.Fatalf("expected total duration 15s, got %v (err=%v)", total, err)
+	}
+
+	pod := readFixture(t, `<VAST version="4.2">
+  <Ad id="1" sequence="1"><InLine><Creatives><Creative><Linear><Duration>00:00:15</Duration></Linear></Creative></Creatives></InLine></Ad>
+  <Ad id="2" sequence="2"><InLine><Creatives><Creative><Linear><Duration>00:00:30.500</Duration></Linear></Creative></Creatives></InLine></Ad>
+</VAST>`)
+	got, ok = pod.PrimaryDuration()
+	if !ok || got != 15*time.Second {
+		t.Fatalf("expected pod primary duration 15s, got %v (ok=%v)", got, ok)
+	}
+	total, err = pod.TotalDuration()
+	if err != nil || total != 45500*time.Millisecond {
+		t.Fatalf("expected pod total duration 45.5s, got %v (err=%v)", total, err)
+	}
+
+	pod.Ad[1].InLine.Creatives.Creative[0].Linear.Duration = "30s"
+	if _, err := pod.TotalDuration(); err == nil || !strings.HasPrefix(err.Error(), "Ad[2]:") {
+		t.Fatalf("expected error for the malformed duration of Ad[2], got %v", err)
+	}
+	if _, ok := New().PrimaryDuration(); ok {
+		t.Fatalf("expected no primary duration for empty document")
+	}
+}
+
+func TestVAST_UsesVPAID(t *testing.T) {
+	vpaid := `<VAST version="4.0"><Ad id="1"><InLine><Creatives><Creative><Linear><Duration>00:00:15</Duration><MediaFiles><MediaFile delivery="progressive" type="application/javascript" width="640" height="360" apiFramework="VPAID"><![CDATA[https://example.com/ad.js]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`
+	if !readFixture(t, vpaid).UsesVPAID() {
+		t.Fatalf("expected VPAID MediaFile to be detected")
+	}
+	simid := `<VAST version="4.0"><Ad id="1"><InLine><Creatives><Creative><Linear><Duration>00:00:15</Duration><MediaFiles><MediaFile delivery="progressive" type="application/javascript" width="640" height="360" apiFramework="SIMID"><![CDATA[https://example.com/ad.js]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`
+	if readFixture(t, simid).UsesVPAID() {
+		t.Fatalf("expected SIMID MediaFile not to be reported as VPAID")
+	}
+	if readFixture(t, walkFixture).UsesVPAID() {
+		t.Fatalf("expected plain video fixture not to use VPAID")
+	}
+}
+
+func TestRead_PreservesAttributeOrder(t *testing.T) {
+	raw := `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <TrackingEvents>
+              <Tracking offset="00:00:05" event="progress"><![CDATA[https://example.com/progress]]></Tracking>
+            </TrackingEvents>
+            <MediaFiles>
+              <MediaFile width="640" height="360" type="video/mp4" bitrate="800" delivery="progressive" id="mf-1"><![CDATA[https://example.com/video.mp4]]></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`
+	doc := readFixture(t, raw)
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	wantMediaFile := `<MediaFile width="640" height="360" type="video/mp4" bitrate="800" delivery="progressive" id="mf-1"><![CDATA[https://example.com/video.mp4]]></MediaFile>`
+	if !strings.Contains(string(out), wantMediaFile) {
+		t.Fatalf("expected MediaFile attributes in source order, got:\n%s", out)
+	}
+	wantTracking := `<Tracking offset="00:00:05" event="progress"><![CDATA[https://example.com/progress]]></Tracking>`
+	if !strings.Contains(string(out), wantTracking) {
+		t.Fatalf("expected Tracking attributes in source order, got:\n%s", out)
+	}
+
+	// A second round trip is stable.
+	again, err := readFixture(t, string(out)).Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	if string(again) != string(out) {
+		t.Fatalf("expected stable round trip, got:\n%s\nthen:\n%s", out, again)
+	}
+
+	// Files built in code keep struct field order.
+	built, err := xml.Marshal(MediaFile{Value: "https://example.com/video.mp4", Delivery: ProgressiveDelivery, Type: "video/mp4", Width: 640, Height: 360})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `<MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://example.com/video.mp4]]></MediaFile>`; string(built) != want {
+		t.Fatalf("expected %s, got %s", want, built)
+	}
+}
+
+func TestCData_UnmarshalMixedContent(t *testing.T) {
+	var got struct {
+		Error CData `xml:"Error"`
+	}
+	raw := `<VAST><Error>https://example.com/error?code=<![CDATA[[ERRORCODE]&ts=]]>[TIMESTAMP]</Error></VAST>`
+	if err := xml.Unmarshal([]byte(raw), &got); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if want := "https://example.com/error?code=[ERRORCODE]&ts=[TIMESTAMP]"; got.Error.Value != want {
+		t.Fatalf("expected %q, got %q", want, got.Error.Value)
+	}
+
+	out, err := xml.Marshal(got.Error)
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `<CData><![CDATA[https://example.com/error?code=[ERRORCODE]&ts=[TIMESTAMP]]]></CData>`; string(out) != want {
+		t.Fatalf("expected %s, got %s", want, out)
+	}
+}
+
+func TestRead_PreservesUnknownExtensionElements(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+	<Ad id="1">
+		<InLine>
+			<AdSystem>Example</AdSystem>
+			<AdTitle>Example</AdTitle>
+			<Creatives>
+				<Creative>
+					<CreativeExtensions>
+						<CreativeExtension type="partner"><PartnerNode score="7"><Nested>value</Nested></PartnerNode></CreativeExtension>
+					</CreativeExtensions>
+				</Creative>
+			</Creatives>
+			<Extensions>
+				<Extension type="partner"><PartnerConfig mode="strict">on</PartnerConfig></Extension>
+			</Extensions>
+		</InLine>
+	</Ad>
+</VAST>`)
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	for _, want := range []string{
+		`<CreativeExtension type="partner"><PartnerNode score="7"><Nested>value</Nested></PartnerNode></CreativeExtension>`,
+		`<PartnerConfig mode="strict">on</PartnerConfig>`,
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Fatalf("expected %s to survive re-marshaling, got:\n%s", want, out)
+		}
+	}
+
+	// Extensions built in code still marshal their items.
+	built, err := xml.Marshal(CreativeExtension{Type: "partner", Items: []string{"value"}})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if want := `<CreativeExtension type="partner"><Items>value</Items></CreativeExtension>`; string(built) != want {
+		t.Fatalf("expected %s, got %s", want, built)
+	}
+}
+
+func TestDuration_ValidateDuration(t *testing.T) {
+	tests := []struct {
+		value   Duration
+		wantErr bool
+	}{
+		{value: "00:00:15"},
+		{value: "00:00:30.500"},
+		{value: "00:00:00", wantErr: true},
+		{value: "00:00:03", wantErr: true},
+		{value: "24:00:00", wantErr: true},
+		{value: "00:60:00", wantErr: true},
+		{value: "00:00:75", wantErr: true},
+		{value: "0:00:15", wantErr: true},
+	}
+	for _, tc := range tests {
+		if err := tc.value.ValidateDuration(); (err != nil) != tc.wantErr {
+			t.Fatalf("ValidateDuration(%s) error = %v, wantErr %v", tc.value, err, tc.wantErr)
+		}
+	}
+}
+
+func TestParseAndParseString(t *testing.T) {
+	fromBytes, err := Parse([]byte(walkFixture))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	fromString, err := ParseString(walkFixture)
+	if err != nil {
+		t.Fatalf("ParseString returned error: %v", err)
+	}
+	if !Equal(fromBytes, fromString) {
+		t.Fatalf("expected Parse and ParseString to decode the same document")
+	}
+	if len(fromString.Ad) == 0 {
+		t.Fatalf("expected ads to be decoded")
+	}
+
+	if _, err := ParseString("<VAST"); !errors.Is(err, ErrUnmarshalVAST) {
+		t.Fatalf("expected ErrUnmarshalVAST, got %v", err)
+	}
+	if _, err := Parse(nil); !errors.Is(err, ErrUnmarshalVAST) {
+		t.Fatalf("expected ErrUnmarshalVAST for empty input, got %v", err)
+	}
+}
+
+func TestVAST_StripTracking(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Impression><![CDATA[https://example.com/imp]]></Impression>
+      <ViewableImpression><Viewable><![CDATA[https://example.com/viewable]]></Viewable></ViewableImpression>
+      <AdVerifications>
+        <Verification vendor="example.com">
+          <JavaScriptResource apiFramework="omid"><![CDATA[https://example.com/omid.js]]></JavaScriptResource>
+          <TrackingEvents><Tracking event="verificationNotExecuted"><![CDATA[https://example.com/vne]]></Tracking></TrackingEvents>
+        </Verification>
+      </AdVerifications>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <TrackingEvents><Tracking event="start"><![CDATA[https://example.com/start]]></Tracking></TrackingEvents>
+            <VideoClicks>
+              <ClickThrough><![CDATA[https://example.com/landing]]></ClickThrough>
+              <ClickTracking><![CDATA[https://example.com/click]]></ClickTracking>
+              <CustomClick><![CDATA[https://example.com/custom]]></CustomClick>
+            </VideoClicks>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360"><![CDATA[https://example.com/video.mp4]]></MediaFile>
+            </MediaFiles>
+            <Icons>
+              <Icon program="AdChoices">
+                <StaticResource creativeType="image/png"><![CDATA[https://example.com/icon.png]]></StaticResource>
+                <IconClicks>
+                  <IconClickThrough><![CDATA[https://example.com/adchoices]]></IconClickThrough>
+                  <IconClickTracking><![CDATA[https://example.com/iconclick]]></IconClickTracking>
+                </IconClicks>
+                <IconViewTracking><![CDATA[https://example.com/iconview]]></IconViewTracking>
+              </Icon>
+            </Icons>
+          </Linear>
+        </Creative>
+        <Creative>
+          <CompanionAds>
+            <Companion width="300" height="250">
+              <StaticResource creativeType="image/png"><![CDATA[https://example.com/companion.png]]></StaticResource>
+              <CompanionClickThrough><![CDATA[https://example.com/companion-landing]]></CompanionClickThrough>
+              <CompanionClickTracking><![CDATA[https://example.com/companion-click]]></CompanionClickTracking>
+              <TrackingEvents><Tracking event="creativeView"><![CDATA[https://example.com/view]]></Tracking></TrackingEvents>
+            </Companion>
+          </CompanionAds>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`)
+
+	doc.StripTracking()
+
+	if urls := doc.TrackingURLs(); len(urls) != 0 {
+		t.Fatalf("expected no tracking URLs, got %v", urls)
+	}
+	out, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes returned error: %v", err)
+	}
+	for _, name := range []string{"<Impression", "<ViewableImpression", "<TrackingEvents", "<ClickTracking", "<CustomClick", "<CompanionClickTracking", "<IconClickTracking", "<IconViewTracking"} {
+		if strings.Contains(string(out), name) {
+			t.Fatalf("expected %s to be stripped, got %s", name, out)
+		}
+	}
+	for _, kept := range []string{"https://example.com/video.mp4", "https://example.com/landing", "https://example.com/companion-landing", "https://example.com/adchoices", "https://example.com/omid.js"} {
+		if !strings.Contains(string(out), kept) {
+			t.Fatalf("expected %s to be kept, got %s", kept, out)
+		}
+	}
+}
+
+func TestVAST_SelectMediaFile(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+  <Ad id="1">
+    <InLine>
+      <AdSystem>Example</AdSystem>
+      <AdTitle>Example</AdTitle>
+      <Creatives>
+        <Creative>
+          <Linear>
+            <Duration>00:00:15</Duration>
+            <MediaFiles>
+              <MediaFile delivery="progressive" type="video/mp4" width="1920" height="1080" bitrate="4500"><![CDATA[https://example.com/1080.mp4]]></MediaFile>
+              <MediaFile delivery="streaming" type="application/x-mpegURL" width="0" height="0"><![CDATA[https://example.com/master.m3u8]]></MediaFile>
+              <MediaFile delivery="progressive" type="video/webm" width="1280" height="720" bitrate="2500"><![CDATA[https://example.com/720.webm]]></MediaFile>
+              <MediaFile delivery="progressive" type="video/mp4" width="640" height="360" bitrate="800"><![CDATA[https://example.com/360.mp4]]></MediaFile>
+              <MediaFile delivery="streaming" type="video/mp4" width="1280" height="720" minBitrate="1200" maxBitrate="3000"><![CDATA[https://example.com/adaptive.mp4]]></MediaFile>
+              <MediaFile delivery="progressive" type="video/mp4" width="1280" height="720" bitrate="2000"></MediaFile>
+            </MediaFiles>
+          </Linear>
+        </Creative>
+      </Creatives>
+    </InLine>
+  </Ad>
+</VAST>`)
+
+	tests := []struct {
+		name       string
+		maxBitrate int
+		mimeTypes  []string
+		want       string
+	}{
+		{name: "no constraints", want: "https://example.com/1080.mp4"},
+		{name: "capped", maxBitrate: 3000, want: "https://example.com/720.webm"},
+		{name: "capped mp4", maxBitrate: 3000, mimeTypes: []string{"VIDEO/MP4"}, want: "https://example.com/adaptive.mp4"},
+		{name: "low cap", maxBitrate: 1000, mimeTypes: []string{"video/mp4"}, want: "https://example.com/360.mp4"},
+		{name: "unknown bitrate only", maxBitrate: 500, mimeTypes: []string{"video/mp4", "application/x-mpegURL"}, want: "https://example.com/master.m3u8"},
+	}
+	for _, tc := range tests {
+		file, ok := doc.SelectMediaFile(tc.maxBitrate, tc.mimeTypes)
+		if !ok || file.Value != tc.want {
+			t.Fatalf("%s: expected %s, got %+v (ok=%v)", tc.name, tc.want, file, ok)
+		}
+	}
+
+	if file, ok := doc.SelectMediaFile(500, []string{"video/mp4"}); ok {
+		t.Fatalf("expected no match under 500 kbps, got %s", file.Value)
+	}
+	if _, ok := (&VAST{}).SelectMediaFile(0, nil); ok {
+		t.Fatalf("expected no match in an empty document")
+	}
+}
+
+func TestVAST_AdAndCreativeCount(t *testing.T) {
+	doc := readFixture(t, `<VAST version="4.2">
+	<Ad id="1">
+		<InLine>
+			<Creatives>
+				<Creative><Linear><Duration>00:00:15</Duration></Linear></Creative>
+				<Creative><CompanionAds></CompanionAds></Creative>
+			</Creatives>
+		</InLine>
+	</Ad>
+	<Ad id="2">
+		<Wrapper>
+			<VASTAdTagURI><![CDATA[https://example.com/vast.xml]]></VASTAdTagURI>
+			<Creatives>
+				<Creative><Linear></Linear></Creative>
+			</Creatives>
+		</Wrapper>
+	</Ad>
+</VAST>`)
+	if got := doc.AdCount(); got != 2 {
+		t.Fatalf("expected 2 ads, got %d", got)
+	}
+	if got := doc.CreativeCount(); got != 3 {
+		t.Fatalf("expected 3 creatives, got %d", got)
+	}
+
+	var empty *VAST
+	if empty.AdCount() != 0 || empty.CreativeCount() != 0 {
+		t.Fatalf("expected nil document to count zero ads and creatives")
+	}
+}
@@ -0,0 +1,219 @@
+// Code generated by cmd/vastversiongen. DO NOT EDIT.
+package vast
+
+var catalogVersionRules = map[string]versionRule{
+	"Ad>InLine":                                      {Since: "2.0", Node: "InLine"},
+	"Ad>Wrapper":                                     {Since: "2.0", Node: "Wrapper"},
+	"Ad@adType":                                      {Since: "4.1"},
+	"Ad@conditionalAd":                               {Since: "4.0"},
+	"Ad@id":                                          {Since: "2.0"},
+	"Ad@sequence":                                    {Since: "3.0"},
+	"AdParameters@xmlEncoded":                        {Since: "3.0"},
+	"AdSystem@version":                               {Since: "3.0"},
+	"AdVerifications>Verification":                   {Since: "4.0", Node: "Verification"},
+	"Advertiser@id":                                  {Since: "4.1"},
+	"BlockedAdCategories@authority":                  {Since: "3.0"},
+	"Category@authority":                             {Since: "3.0"},
+	"ClickThrough@id":                                {Since: "3.0"},
+	"ClickTracking@id":                               {Since: "3.0"},
+	"ClosedCaptionFile@language":                     {Since: "3.0"},
+	"ClosedCaptionFile@type":                         {Since: "3.0"},
+	"ClosedCaptionFiles>ClosedCaptionFile":           {Since: "3.0", Node: "ClosedCaptionFile"},
+	"Companion>AdParameters":                         {Since: "2.0", Node: "AdParameters"},
+	"Companion>AltText":                              {Since: "2.0", Node: "AltText"},
+	"Companion>CompanionClickThrough":                {Since: "2.0", Node: "CompanionClickThrough"},
+	"Companion>CompanionClickTracking":               {Since: "3.0", Node: "CompanionClickTracking"},
+	"Companion>CreativeExtensions":                   {Since: "3.0", Node: "CreativeExtensions"},
+	"Companion>HTMLResource":                         {Since: "2.0", Node: "HTMLResource"},
+	"Companion>IFrameResource":                       {Since: "2.0", Node: "IFrameResource"},
+	"Companion>StaticResource":                       {Since: "2.0", Node: "StaticResource"},
+	"Companion>TrackingEvents":                       {Since: "2.0", Node: "TrackingEvents"},
+	"Companion@adSlotId":                             {Since: "3.0"},
+	"Companion@apiFramework":                         {Since: "2.0"},
+	"Companion@assetHeight":                          {Since: "3.0"},
+	"Companion@assetWidth":                           {Since: "3.0"},
+	"Companion@expandedHeight":                       {Since: "3.0"},
+	"Companion@expandedWidth":                        {Since: "3.0"},
+	"Companion@height":                               {Since: "2.0"},
+	"Companion@id":                                   {Since: "2.0"},
+	"Companion@pxratio":                              {Since: "3.0"},
+	"Companion@renderingMode":                        {Since: "3.0"},
+	"Companion@width":                                {Since: "2.0"},
+	"CompanionAds>Companion":                         {Since: "2.0", Node: "Companion"},
+	"CompanionAds@required":                          {Since: "3.0"},
+	"CompanionClickTracking@id":                      {Since: "3.0"},
+	"Creative>CompanionAds":                          {Since: "2.0", Node: "CompanionAds"},
+	"Creative>CreativeExtensions":                    {Since: "3.0", Node: "CreativeExtensions"},
+	"Creative>Linear":                                {Since: "2.0", Node: "Linear"},
+	"Creative>NonLinearAds":                          {Since: "2.0", Node: "NonLinearAds"},
+	"Creative>UniversalAdId":                         {Since: "4.0", Node: "UniversalAdId"},
+	"Creative@AdID":                                  {Since: "2.0"},
+	"Creative@apiFramework":                          {Since: "2.0"},
+	"Creative@id":                                    {Since: "2.0"},
+	"Creative@sequence":                              {Since: "2.0"},
+	"CreativeExtension@type":                         {Since: "3.0"},
+	"CreativeExtensions>CreativeExtension":           {Since: "3.0", Node: "CreativeExtension"},
+	"Creatives>Creative":                             {Since: "2.0", Node: "Creative"},
+	"CustomClick@id":                                 {Since: "3.0"},
+	"ExecutableResource@apiFramework":                {Since: "3.0"},
+	"ExecutableResource@language":                    {Since: "4.1"},
+	"ExecutableResource@type":                        {Since: "3.0"},
+	"Extension@type":                                 {Since: "2.0"},
+	"Extensions>Extension":                           {Since: "2.0", Node: "Extension"},
+	"Icon>HTMLResource":                              {Since: "3.0", Node: "HTMLResource"},
+	"Icon>IFrameResource":                            {Since: "3.0", Node: "IFrameResource"},
+	"Icon>IconClicks":                                {Since: "3.0", Node: "IconClicks"},
+	"Icon>IconViewTracking":                          {Since: "3.0", Node: "IconViewTracking"},
+	"Icon>StaticResource":                            {Since: "3.0", Node: "StaticResource"},
+	"Icon@apiFramework":                              {Since: "3.0"},
+	"Icon@duration":                                  {Since: "3.0"},
+	"Icon@height":                                    {Since: "3.0"},
+	"Icon@offset":                                    {Since: "3.0"},
+	"Icon@program":                                   {Since: "3.0"},
+	"Icon@pxratio":                                   {Since: "3.0"},
+	"Icon@width":                                     {Since: "3.0"},
+	"Icon@xPosition":                                 {Since: "3.0"},
+	"Icon@yPosition":                                 {Since: "3.0"},
+	"IconClickFallbackImage>AltText":                 {Since: "4.2", Node: "AltText"},
+	"IconClickFallbackImage>StaticResource":          {Since: "4.2", Node: "StaticResource"},
+	"IconClickFallbackImage@height":                  {Since: "4.2"},
+	"IconClickFallbackImage@width":                   {Since: "4.2"},
+	"IconClickFallbackImages>IconClickFallbackImage": {Since: "4.2", Node: "IconClickFallbackImage"},
+	"IconClickTracking@id":                           {Since: "3.0"},
+	"IconClicks>IconClickFallbackImages":             {Since: "4.2", Node: "IconClickFallbackImages"},
+	"IconClicks>IconClickThrough":                    {Since: "3.0", Node: "IconClickThrough"},
+	"IconClicks>IconClickTracking":                   {Since: "3.0", Node: "IconClickTracking"},
+	"Icons>Icon":                                     {Since: "3.0", Node: "Icon"},
+	"Impression@id":                                  {Since: "3.0"},
+	"InLine>AdServingId":                             {Since: "4.1", Node: "AdServingId"},
+	"InLine>AdSystem":                                {Since: "2.0", Node: "AdSystem"},
+	"InLine>AdTitle":                                 {Since: "2.0", Node: "AdTitle"},
+	"InLine>AdVerifications":                         {Since: "4.0", Node: "AdVerifications"},
+	"InLine>Advertiser":                              {Since: "3.0", Node: "Advertiser"},
+	"InLine>Category":                                {Since: "3.0", Node: "Category"},
+	"InLine>Creatives":                               {Since: "2.0", Node: "Creatives"},
+	"InLine>Description":                             {Since: "2.0", Node: "Description"},
+	"InLine>Error":                                   {Since: "2.0", Node: "Error"},
+	"InLine>Expires":                                 {Since: "3.0", Node: "Expires"},
+	"InLine>Extensions":                              {Since: "2.0", Node: "Extensions"},
+	"InLine>Impression":                              {Since: "2.0", Node: "Impression"},
+	"InLine>Pricing":                                 {Since: "3.0", Node: "Pricing"},
+	"InLine>Survey":                                  {Since: "2.0", Node: "Survey"},
+	"InLine>ViewableImpression":                      {Since: "4.0", Node: "ViewableImpression"},
+	"InteractiveCreativeFile@apiFramework":           {Since: "3.0"},
+	"InteractiveCreativeFile@type":                   {Since: "3.0"},
+	"InteractiveCreativeFile@variableDuration":       {Since: "3.0"},
+	"JavaScriptResource@apiFramework":                {Since: "3.0"},
+	"JavaScriptResource@browserOptional":             {Since: "3.0"},
+	"Linear>AdParameters":                            {Since: "2.0", Node: "AdParameters"},
+	"Linear>Duration":                                {Since: "2.0", Node: "Duration"},
+	"Linear>Icons":                                   {Since: "3.0", Node: "Icons"},
+	"Linear>MediaFiles":                              {Since: "2.0", Node: "MediaFiles"},
+	"Linear>TrackingEvents":                          {Since: "2.0", Node: "TrackingEvents"},
+	"Linear>VideoClicks":                             {Since: "2.0", Node: "VideoClicks"},
+	"Linear@skipoffset":                              {Since: "3.0"},
+	"MediaFile@apiFramework":                         {Since: "2.0"},
+	"MediaFile@bitrate":                              {Since: "3.0"},
+	"MediaFile@codec":                                {Since: "3.0"},
+	"MediaFile@delivery":                             {Since: "2.0"},
+	"MediaFile@fileSize":                             {Since: "3.0"},
+	"MediaFile@height":                               {Since: "2.0"},
+	"MediaFile@id":                                   {Since: "2.0"},
+	"MediaFile@maintainAspectRatio":                  {Since: "2.0"},
+	"MediaFile@maxBitrate":                           {Since: "3.0"},
+	"MediaFile@mediaType":                            {Since: "3.0"},
+	"MediaFile@minBitrate":                           {Since: "3.0"},
+	"MediaFile@scalable":                             {Since: "2.0"},
+	"MediaFile@type":                                 {Since: "2.0"},
+	"MediaFile@width":                                {Since: "2.0"},
+	"MediaFiles>ClosedCaptionFiles":                  {Since: "3.0", Node: "ClosedCaptionFiles"},
+	"MediaFiles>InteractiveCreativeFile":             {Since: "3.0", Node: "InteractiveCreativeFile"},
+	"MediaFiles>MediaFile":                           {Since: "2.0", Node: "MediaFile"},
+	"MediaFiles>Mezzanine":                           {Since: "4.0", Node: "Mezzanine"},
+	"Mezzanine@bitrate":                              {Since: "4.0"},
+	"Mezzanine@codec":                                {Since: "4.0"},
+	"Mezzanine@delivery":                             {Since: "4.0"},
+	"Mezzanine@fileSize":                             {Since: "4.1"},
+	"Mezzanine@height":                               {Since: "4.0"},
+	"Mezzanine@id":                                   {Since: "4.0"},
+	"Mezzanine@maintainAspectRatio":                  {Since: "4.0"},
+	"Mezzanine@maxBitrate":                           {Since: "4.0"},
+	"Mezzanine@mediaType":                            {Since: "4.0"},
+	"Mezzanine@minBitrate":                           {Since: "4.0"},
+	"Mezzanine@scalable":                             {Since: "4.0"},
+	"Mezzanine@type":                                 {Since: "4.0"},
+	"Mezzanine@width":                                {Since: "4.0"},
+	"NonLinear>AdParameters":                         {Since: "2.0", Node: "AdParameters"},
+	"NonLinear>HTMLResource":                         {Since: "2.0", Node: "HTMLResource"},
+	"NonLinear>IFrameResource":                       {Since: "2.0", Node: "IFrameResource"},
+	"NonLinear>NonLinearClickThrough":                {Since: "2.0", Node: "NonLinearClickThrough"},
+	"NonLinear>NonLinearClickTracking":               {Since: "3.0", Node: "NonLinearClickTracking"},
+	"NonLinear>StaticResource":                       {Since: "2.0", Node: "StaticResource"},
+	"NonLinear@apiFramework":                         {Since: "2.0"},
+	"NonLinear@expandedHeight":                       {Since: "3.0"},
+	"NonLinear@expandedWidth":                        {Since: "3.0"},
+	"NonLinear@height":                               {Since: "2.0"},
+	"NonLinear@id":                                   {Since: "2.0"},
+	"NonLinear@maintainAspectRatio":                  {Since: "2.0"},
+	"NonLinear@minSuggestedDuration":                 {Since: "2.0"},
+	"NonLinear@scalable":                             {Since: "2.0"},
+	"NonLinear@width":                                {Since: "2.0"},
+	"NonLinearAds>NonLinear":                         {Since: "2.0", Node: "NonLinear"},
+	"NonLinearAds>TrackingEvents":                    {Since: "2.0", Node: "TrackingEvents"},
+	"NonLinearClickTracking@id":                      {Since: "3.0"},
+	"Pricing@currency":                               {Since: "3.0"},
+	"Pricing@model":                                  {Since: "3.0"},
+	"StaticResource@creativeType":                    {Since: "2.0"},
+	"Survey@type":                                    {Since: "2.0"},
+	"Tracking@event":                                 {Since: "2.0"},
+	"Tracking@offset":                                {Since: "3.0"},
+	"TrackingEvents>Tracking":                        {Since: "2.0", Node: "Tracking"},
+	"UniversalAdId@idRegistry":                       {Since: "4.0"},
+	"UniversalAdId@idValue":                          {Since: "4.0"},
+	"VAST>Ad":                                        {Since: "2.0", Node: "Ad"},
+	"VAST>Error":                                     {Since: "2.0", Node: "Error"},
+	"VAST@version":                                   {Since: "2.0"},
+	"Verification>BlockedAdCategories":               {Since: "4.1", Node: "BlockedAdCategories"},
+	"Verification>ExecutableResource":                {Since: "4.0", Node: "ExecutableResource"},
+	"Verification>JavaScriptResource":                {Since: "4.0", Node: "JavaScriptResource"},
+	"Verification>TrackingEvents":                    {Since: "4.0", Node: "TrackingEvents"},
+	"Verification>VerificationParameters":            {Since: "4.0", Node: "VerificationParameters"},
+	"Verification@vendor":                            {Since: "3.0"},
+	"VideoClicks>ClickThrough":                       {Since: "2.0", Node: "ClickThrough"},
+	"VideoClicks>ClickTracking":                      {Since: "2.0", Node: "ClickTracking"},
+	"VideoClicks>CustomClick":                        {Since: "3.0", Node: "CustomClick"},
+	"ViewableImpression>NotViewable":                 {Since: "4.0", Node: "NotViewable"},
+	"ViewableImpression>ViewUndetermined":            {Since: "4.0", Node: "ViewUndetermined"},
+	"ViewableImpression>Viewable":                    {Since: "4.0", Node: "Viewable"},
+	"ViewableImpression@id":                          {Since: "4.2"},
+	"Wrapper>AdSystem":                               {Since: "2.0", Node: "AdSystem"},
+	"Wrapper>AdVerifications":                        {Since: "4.0", Node: "AdVerifications"},
+	"Wrapper>BlockedAdCategories":                    {Since: "3.0", Node: "BlockedAdCategories"},
+	"Wrapper>Creatives":                              {Since: "2.0", Node: "WrapperCreatives"},
+	"Wrapper>Error":                                  {Since: "2.0", Node: "Error"},
+	"Wrapper>Extensions":                             {Since: "2.0", Node: "Extensions"},
+	"Wrapper>Impression":                             {Since: "2.0", Node: "Impression"},
+	"Wrapper>Pricing":                                {Since: "4.0", Node: "Pricing"},
+	"Wrapper>VASTAdTagURI":                           {Since: "2.0", Node: "VASTAdTagURI"},
+	"Wrapper>ViewableImpression":                     {Since: "4.0", Node: "ViewableImpression"},
+	"Wrapper@allowMultipleAds":                       {Since: "4.0"},
+	"Wrapper@fallbackOnNoAd":                         {Since: "4.0"},
+	"Wrapper@followAdditionalWrappers":               {Since: "4.0"},
+	"WrapperCreative>CompanionAds":                   {Since: "2.0", Node: "CompanionAds"},
+	"WrapperCreative>CreativeExtensions":             {Since: "3.0", Node: "CreativeExtensions"},
+	"WrapperCreative>Linear":                         {Since: "2.0", Node: "WrapperLinear"},
+	"WrapperCreative>NonLinearAds":                   {Since: "2.0", Node: "NonLinearAds"},
+	"WrapperCreative>UniversalAdId":                  {Since: "4.0", Node: "UniversalAdId"},
+	"WrapperCreative@AdID":                           {Since: "2.0"},
+	"WrapperCreative@apiFramework":                   {Since: "2.0"},
+	"WrapperCreative@id":                             {Since: "2.0"},
+	"WrapperCreative@sequence":                       {Since: "2.0"},
+	"WrapperCreatives>Creative":                      {Since: "2.0", Node: "WrapperCreative"},
+	"WrapperLinear>AdParameters":                     {Since: "2.0", Node: "AdParameters"},
+	"WrapperLinear>Duration":                         {Since: "2.0", Node: "Duration"},
+	"WrapperLinear>Icons":                            {Since: "3.0", Node: "Icons"},
+	"WrapperLinear>MediaFiles":                       {Since: "2.0", Node: "MediaFiles"},
+	"WrapperLinear>TrackingEvents":                   {Since: "2.0", Node: "TrackingEvents"},
+	"WrapperLinear>VideoClicks":                      {Since: "2.0", Node: "VideoClicks"},
+	"WrapperLinear@skipoffset":                       {Since: "3.0"},
+}
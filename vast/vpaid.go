@@ -0,0 +1,36 @@
+package vast
+
+import "strings"
+
+// VPAIDAPIFramework is the apiFramework value identifying VPAID creatives.
+const VPAIDAPIFramework = "VPAID"
+
+// UsesVPAID reports whether any creative, MediaFile, InteractiveCreativeFile,
+// NonLinear, Companion or Icon declares the VPAID apiFramework.
+func (v *VAST) UsesVPAID() bool {
+	found := false
+	v.Walk(func(node any) {
+		if found {
+			return
+		}
+		var apiFramework string
+		switch typed := node.(type) {
+		case *InLineCreative:
+			apiFramework = typed.APIFramework
+		case *WrapperCreative:
+			apiFramework = typed.APIFramework
+		case *MediaFile:
+			apiFramework = typed.APIFramework
+		case *InteractiveCreativeFile:
+			apiFramework = typed.APIFramework
+		case *NonLinearAd:
+			apiFramework = typed.APIFramework
+		case *CompanionAd:
+			apiFramework = typed.APIFramework
+		case *Icon:
+			apiFramework = typed.APIFramework
+		}
+		found = strings.EqualFold(strings.TrimSpace(apiFramework), VPAIDAPIFramework)
+	})
+	return found
+}
@@ -0,0 +1,233 @@
+package vast
+
+// Walk visits the ads, creatives, beacons, clicks, media files and resources of
+// the document depth-first in document order. Each value is passed as a pointer
+// into the tree so callers can mutate it in place, e.g. to rewrite URLs.
+//
+// Visited types are *Ad, *InLine, *Wrapper, *Verification, *InLineCreative,
+// *WrapperCreative, *LinearInLine, *LinearWrapper, *VideoClicks, *NonLinearAd,
+// *CompanionAd, *Icon, *Impression, *Tracking, *ClickThrough, *MediaFile,
+// *Mezzanine, *InteractiveCreativeFile, *ClosedCaptionFile, *StaticResource,
+// *HTMLResource, *IFrameResource, *JavaScriptResource, *ExecutableResource and
+// *CData. URL fields modeled as plain strings (CustomClick, CompanionClickTracking,
+// IconClickThrough, IconClickTracking and IconViewTracking) are passed as *string.
+func (v *VAST) Walk(fn func(node any)) {
+	if v == nil || fn == nil {
+		return
+	}
+	walkCData(v.Error, fn)
+	for i := range v.Ad {
+		ad := &v.Ad[i]
+		fn(ad)
+		if ad.InLine != nil {
+			walkInLine(ad.InLine, fn)
+		}
+		if ad.Wrapper != nil {
+			walkWrapper(ad.Wrapper, fn)
+		}
+	}
+}
+
+func walkInLine(inline *InLine, fn func(node any)) {
+	fn(inline)
+	walkAdDefinition(&inline.AdDefinition, fn)
+	walkAdVerifications(inline.AdVerifications, fn)
+	for i := range inline.Creatives.Creative {
+		creative := &inline.Creatives.Creative[i]
+		fn(creative)
+		if creative.Linear != nil {
+			linear := creative.Linear
+			fn(linear)
+			walkLinear(&linear.Linear, fn)
+			walkVideoClicks(linear.VideoClicks, fn)
+			walkMediaFiles(&linear.MediaFiles, fn)
+		}
+		walkNonLinearAds(creative.NonLinearAds, fn)
+		walkCompanionAds(creative.CompanionAds, fn)
+	}
+}
+
+func walkWrapper(wrapper *Wrapper, fn func(node any)) {
+	fn(wrapper)
+	walkAdDefinition(&wrapper.AdDefinition, fn)
+	fn(&wrapper.VASTAdTagURI)
+	walkAdVerifications(wrapper.AdVerifications, fn)
+	if wrapper.Creatives == nil {
+		return
+	}
+	for i := range wrapper.Creatives.Creative {
+		creative := &wrapper.Creatives.Creative[i]
+		fn(creative)
+		if creative.Linear != nil {
+			linear := creative.Linear
+			fn(linear)
+			walkLinear(&linear.Linear, fn)
+			walkVideoClicks(linear.VideoClicks, fn)
+		}
+		walkNonLinearAds(creative.NonLinearAds, fn)
+		walkCompanionAds(creative.CompanionAds, fn)
+	}
+}
+
+func walkAdDefinition(def *AdDefinition, fn func(node any)) {
+	walkCData(def.Error, fn)
+	for i := range def.Impression {
+		fn(&def.Impression[i])
+	}
+	if def.ViewableImpression != nil {
+		walkCData(def.ViewableImpression.Viewable, fn)
+		walkCData(def.ViewableImpression.NotViewable, fn)
+		walkCData(def.ViewableImpression.ViewUndetermined, fn)
+	}
+}
+
+func walkAdVerifications(verifications *AdVerifications, fn func(node any)) {
+	if verifications == nil {
+		return
+	}
+	for i := range verifications.Verification {
+		verification := &verifications.Verification[i]
+		fn(verification)
+		for j := range verification.JavaScriptResource {
+			fn(&verification.JavaScriptResource[j])
+		}
+		for j := range verification.ExecutableResource {
+			fn(&verification.ExecutableResource[j])
+		}
+		if verification.TrackingEvents != nil {
+			walkTracking(verification.TrackingEvents.Tracking, fn)
+		}
+	}
+}
+
+func walkLinear(linear *Linear, fn func(node any)) {
+	if linear.Icons != nil {
+		for i := range linear.Icons.Icon {
+			walkIcon(&linear.Icons.Icon[i], fn)
+		}
+	}
+	walkTrackingEvents(linear.TrackingEvents, fn)
+}
+
+func walkVideoClicks(clicks *VideoClicks, fn func(node any)) {
+	if clicks == nil {
+		return
+	}
+	fn(clicks)
+	fn(&clicks.ClickThrough)
+	walkCData(clicks.ClickTracking, fn)
+	walkStrings(clicks.CustomClick, fn)
+}
+
+func walkMediaFiles(files *MediaFiles, fn func(node any)) {
+	for i := range files.MediaFile {
+		fn(&files.MediaFile[i])
+	}
+	for i := range files.Mezzanine {
+		fn(&files.Mezzanine[i])
+	}
+	for i := range files.InteractiveCreativeFile {
+		fn(&files.InteractiveCreativeFile[i])
+	}
+	if files.ClosedCaptionFiles != nil {
+		for i := range files.ClosedCaptionFiles.ClosedCaptionFile {
+			fn(&files.ClosedCaptionFiles.ClosedCaptionFile[i])
+		}
+	}
+}
+
+func walkNonLinearAds(ads *NonLinearAds, fn func(node any)) {
+	if ads == nil {
+		return
+	}
+	walkTrackingEvents(ads.TrackingEvents, fn)
+	for i := range ads.NonLinear {
+		nonLinear := &ads.NonLinear[i]
+		fn(nonLinear)
+		walkStaticResources(nonLinear.StaticResource, fn)
+		walkCData(nonLinear.IFrameResource, fn)
+		walkCData(nonLinear.HTMLResource, fn)
+		if nonLinear.NonLinearClickThrough != nil {
+			fn(nonLinear.NonLinearClickThrough)
+		}
+		walkCData(nonLinear.NonLinearClickTracking, fn)
+	}
+}
+
+func walkCompanionAds(ads *CompanionAds, fn func(node any)) {
+	if ads == nil {
+		return
+	}
+	for i := range ads.Companion {
+		companion := &ads.Companion[i]
+		fn(companion)
+		walkStaticResources(companion.StaticResource, fn)
+		walkCData(companion.IFrameResource, fn)
+		walkCData(companion.HTMLResource, fn)
+		if companion.CompanionClickThrough != nil {
+			fn(companion.CompanionClickThrough)
+		}
+		walkStrings(companion.CompanionClickTracking, fn)
+		walkTrackingEvents(companion.TrackingEvents, fn)
+	}
+}
+
+func walkIcon(icon *Icon, fn func(node any)) {
+	fn(icon)
+	walkStaticResources(icon.StaticResource, fn)
+	walkCData(icon.IFrameResource, fn)
+	walkCData(icon.HTMLResource, fn)
+	walkStaticResources(icon.CreativeResource.StaticResource, fn)
+	for i := range icon.CreativeResource.IFrameResource {
+		fn(&icon.CreativeResource.IFrameResource[i])
+	}
+	for i := range icon.CreativeResource.HTMLResource {
+		fn(&icon.CreativeResource.HTMLResource[i])
+	}
+	if icon.IconClicks != nil {
+		if icon.IconClicks.IconClickThrough != "" {
+			fn(&icon.IconClicks.IconClickThrough)
+		}
+		walkStrings(icon.IconClicks.IconClickTracking, fn)
+		if icon.IconClicks.IconClickFallbackImages != nil {
+			images := icon.IconClicks.IconClickFallbackImages.IconClickFallbackImage
+			for i := range images {
+				if images[i].StaticResource != nil {
+					fn(images[i].StaticResource)
+				}
+			}
+		}
+	}
+	walkStrings(icon.IconViewTracking, fn)
+}
+
+func walkTrackingEvents(events *TrackingEvents, fn func(node any)) {
+	if events == nil {
+		return
+	}
+	walkTracking(events.Tracking, fn)
+}
+
+func walkTracking(trackings []Tracking, fn func(node any)) {
+	for i := range trackings {
+		fn(&trackings[i])
+	}
+}
+
+func walkStaticResources(resources []StaticResource, fn func(node any)) {
+	for i := range resources {
+		fn(&resources[i])
+	}
+}
+
+func walkCData(values []CData, fn func(node any)) {
+	for i := range values {
+		fn(&values[i])
+	}
+}
+
+func walkStrings(values []string, fn func(node any)) {
+	for i := range values {
+		fn(&values[i])
+	}
+}
@@ -0,0 +1,70 @@
+package vast
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ErrMissingTagURI is returned by WrapperBuilder.Build when no VASTAdTagURI was set.
+var ErrMissingTagURI = errors.New("wrapper ad requires a non-empty VASTAdTagURI")
+
+// WrapperBuilder assembles a Wrapper Ad step by step. Create one with NewWrapperAd
+// and call Build once all fields are set.
+type WrapperBuilder struct {
+	ad      Ad
+	wrapper Wrapper
+}
+
+// NewWrapperAd starts building a Wrapper Ad with the given id.
+func NewWrapperAd(id string) *WrapperBuilder {
+	return &WrapperBuilder{ad: Ad{ID: id}}
+}
+
+// WithAdSystem sets the AdSystem name and optional version.
+func (b *WrapperBuilder) WithAdSystem(name, version string) *WrapperBuilder {
+	b.wrapper.AdSystem = AdSystem{Value: name, Version: version}
+	return b
+}
+
+// AddImpression appends an Impression beacon.
+func (b *WrapperBuilder) AddImpression(url string) *WrapperBuilder {
+	b.wrapper.Impression = append(b.wrapper.Impression, Impression{Value: url})
+	return b
+}
+
+// WithTagURI sets the VASTAdTagURI pointing at the next VAST document in the chain.
+func (b *WrapperBuilder) WithTagURI(url string) *WrapperBuilder {
+	b.wrapper.VASTAdTagURI = CData{Value: url}
+	return b
+}
+
+// SetFollowAdditionalWrappers sets the followAdditionalWrappers attribute.
+func (b *WrapperBuilder) SetFollowAdditionalWrappers(follow bool) *WrapperBuilder {
+	b.wrapper.FollowAdditionalWrappers = NumericBool(follow)
+	return b
+}
+
+// Build returns the assembled Ad. It fails when the VASTAdTagURI is missing or
+// is not an absolute URL.
+func (b *WrapperBuilder) Build() (*Ad, error) {
+	tagURI := strings.TrimSpace(b.wrapper.VASTAdTagURI.Value)
+	if tagURI == "" {
+		return nil, ErrMissingTagURI
+	}
+	parsed, err := url.Parse(tagURI)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VASTAdTagURI %q: %w", tagURI, err)
+	}
+	if !parsed.IsAbs() || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid VASTAdTagURI %q: must be an absolute URL", tagURI)
+	}
+
+	ad := b.ad
+	wrapper := b.wrapper
+	wrapper.VASTAdTagURI = CData{Value: tagURI}
+	wrapper.Impression = append([]Impression(nil), b.wrapper.Impression...)
+	ad.Wrapper = &wrapper
+	return &ad, nil
+}